@@ -0,0 +1,481 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/cloudwego/kitex/pkg/klog"
+)
+
+// callbackHandler ...
+type callbackHandler func(namespace, group, dataId, data string)
+
+type configParam struct {
+	DataID string
+	Group  string
+}
+
+// NOTE: the nacos client use namespace + dataID + group as cache key, and the namespace
+// in client is fixed.
+func configParamKey(in ConfigParam) configParam {
+	return configParam{
+		DataID: in.DataId,
+		Group:  in.Group,
+	}
+}
+
+// Client the wrapper of nacos client.
+type Client interface {
+	SetParser(ConfigParser)
+	ClientConfigParam(cpc *ConfigParamConfig) (ConfigParam, error)
+	ServerConfigParam(cpc *ConfigParamConfig) (ConfigParam, error)
+	RegisterConfigCallback(ConfigParam, func(string, ConfigParser), int64)
+	DeregisterConfig(ConfigParam, int64) error
+	// History returns the local versions of the config, from the oldest to the latest.
+	History(ConfigParam) []Version
+	// Rollback applies an earlier version locally and pins it until the remote config changes or Unpin is called.
+	Rollback(param ConfigParam, version int64) error
+	// Unpin clears the pinned version and applies the remote config again.
+	Unpin(ConfigParam) error
+	// Status returns the apply status of the config.
+	Status(ConfigParam) Status
+}
+
+type client struct {
+	ncli ConfigClient
+	// support customise parser
+	parser               ConfigParser
+	groupTemplate        *template.Template
+	serverDataIDTemplate *template.Template
+	clientDataIDTemplate *template.Template
+	instance             *Instance
+	decryptor            Decryptor
+	verifier             Verifier
+	unsignedPolicy       UnsignedPolicy
+	signatureSuffix      string
+	strict               bool
+	interpolator         *interpolator
+
+	handlerMutex sync.RWMutex
+	handlers     map[configParam]map[int64]callbackHandler
+
+	history    history
+	includes   includes
+	signatures signatures
+}
+
+// Options the options of the governance client shared by the nacos sdk adapters. All the fields have default value.
+type Options struct {
+	Group              string
+	ServerDataIDFormat string
+	ClientDataIDFormat string
+	ConfigParser       ConfigParser
+	GovernanceOptions
+}
+
+// GovernanceOptions the options of the governance features, which are embedded in the options of the
+// nacos packages as is.
+type GovernanceOptions struct {
+	// Decryptor decrypts the encrypted payloads before decoding, ENC(...) values are kept as is if not set.
+	Decryptor Decryptor
+	// Verifier verifies the signatures before decoding, the signatures are not checked if not set.
+	Verifier Verifier
+	// UnsignedPolicy decides how to handle the unsigned contents when the Verifier is set, UnsignedReject by default.
+	UnsignedPolicy UnsignedPolicy
+	// SignatureSuffix the suffix of the dataId holding the detached signature, NacosDefaultSignatureSuffix by default.
+	SignatureSuffix string
+	// StrictDecoding rejects the contents with unknown or duplicate fields for all the categories,
+	// use utils.WithStrictDecoding for some categories only.
+	StrictDecoding bool
+	// Interpolation resolves the placeholders like ${ENV}, ${ENV:-default} and ${labels.zone} in the
+	// payloads before decoding.
+	Interpolation bool
+	// UnresolvedPolicy decides how to handle the unresolved placeholders, UnresolvedReject by default.
+	UnresolvedPolicy UnresolvedPolicy
+	// HistorySize the number of versions kept for every subscription, NacosDefaultHistorySize by default.
+	HistorySize int
+	// Instance identifies the current process for rollout, empty fields are filled from the environment.
+	Instance Instance
+}
+
+// NewClient creates the governance client talking to nacos through the adapter of the nacos sdk.
+func NewClient(cli ConfigClient, opts Options) (Client, error) {
+	if opts.Group == "" {
+		opts.Group = NacosDefaultConfigGroup
+	}
+	if opts.ConfigParser == nil {
+		opts.ConfigParser = defaultConfigParse()
+	}
+	if opts.ServerDataIDFormat == "" {
+		opts.ServerDataIDFormat = NacosDefaultServerDataID
+	}
+	if opts.ClientDataIDFormat == "" {
+		opts.ClientDataIDFormat = NacosDefaultClientDataID
+	}
+
+	if opts.SignatureSuffix == "" {
+		opts.SignatureSuffix = NacosDefaultSignatureSuffix
+	}
+
+	opts.Instance.fillDefault()
+
+	groupTemplate, err := template.New("group").Parse(opts.Group)
+	if err != nil {
+		return nil, err
+	}
+	serverDataIDTemplate, err := template.New("serverDataID").Parse(opts.ServerDataIDFormat)
+	if err != nil {
+		return nil, err
+	}
+	clientDataIDTemplate, err := template.New("clientDataID").Parse(opts.ClientDataIDFormat)
+	if err != nil {
+		return nil, err
+	}
+	c := &client{
+		ncli:                 cli,
+		parser:               opts.ConfigParser,
+		groupTemplate:        groupTemplate,
+		serverDataIDTemplate: serverDataIDTemplate,
+		clientDataIDTemplate: clientDataIDTemplate,
+		instance:             &opts.Instance,
+		decryptor:            opts.Decryptor,
+		verifier:             opts.Verifier,
+		unsignedPolicy:       opts.UnsignedPolicy,
+		signatureSuffix:      opts.SignatureSuffix,
+		strict:               opts.StrictDecoding,
+		handlers:             map[configParam]map[int64]callbackHandler{},
+		history:              history{size: opts.HistorySize},
+	}
+	if opts.Interpolation {
+		c.interpolator = &interpolator{instance: &opts.Instance, policy: opts.UnresolvedPolicy}
+	}
+	return c, nil
+}
+
+// SetParser support customise parser
+func (c *client) SetParser(parser ConfigParser) {
+	c.parser = parser
+}
+
+// delivery the parser passed to the config callback, which detects the format, unwraps the rollout
+// envelope, decrypts the payload, normalizes the human-friendly values and records whether the callback
+// decoded the content successfully.
+type delivery struct {
+	ConfigParser
+	decoded  bool
+	rejected error
+	dryRun   bool
+	diff     []string
+	strict   bool
+	// kind overrides the kind passed by the callback, it's json if the includes are composed
+	kind string
+}
+
+// Decode decodes the data and records the result.
+func (d *delivery) Decode(kind, data string, config interface{}) error {
+	if d.kind != "" {
+		kind = d.kind
+	}
+	err := d.ConfigParser.Decode(kind, data, config)
+	d.decoded = err == nil
+	if err != nil {
+		d.rejected = err
+	}
+	return err
+}
+
+func (c *client) callbackParser(param ConfigParam) *delivery {
+	d := &delivery{strict: c.strict}
+	var parser ConfigParser = &strictParser{parser: &normalizeParser{parser: c.parser}, strict: &d.strict}
+	if c.decryptor != nil {
+		parser = &decryptParser{parser: parser, decryptor: c.decryptor}
+	}
+	d.ConfigParser = &kindParser{
+		parser: &rolloutParser{
+			parser:   parser,
+			envelope: c.parser,
+			instance: c.instance,
+			dataID:   param.DataId,
+		},
+		dataID: param.DataId,
+	}
+	return d
+}
+
+// prepare verifies the signature, decrypts the whole payload and resolves the placeholders of the data,
+// so the placeholders inside the encrypted payload are resolved and the format is detected on the plaintext.
+func (c *client) prepare(param ConfigParam, data string) (string, error) {
+	payload, err := c.verify(param, data)
+	if err != nil {
+		return "", err
+	}
+	return c.resolve(payload)
+}
+
+// resolve decrypts the whole payload and resolves the placeholders of the verified payload.
+func (c *client) resolve(payload string) (string, error) {
+	if c.decryptor != nil {
+		d := &decryptParser{decryptor: c.decryptor}
+		if value, ok, err := d.decrypt(strings.TrimSpace(payload)); ok {
+			if err != nil {
+				return "", err
+			}
+			payload = value
+		}
+	}
+	if c.interpolator == nil {
+		return payload, nil
+	}
+	return c.interpolator.interpolate(payload)
+}
+
+// preprocess resolves the verified payload and composes the included dataIds, true if the payload is
+// composed into json.
+func (c *client) preprocess(param ConfigParam, verified string) (string, bool, error) {
+	payload, err := c.resolve(verified)
+	if err != nil {
+		return "", false, err
+	}
+	return c.compose(param, payload)
+}
+
+// deliver verifies and preprocesses the data, invokes the callback and records the content with its
+// verified payload into history once it is decoded and applied.
+func (c *client) deliver(param ConfigParam, data string, callback func(string, ConfigParser)) {
+	verified, err := c.verify(param, data)
+	var payload string
+	var composed bool
+	if err == nil {
+		payload, composed, err = c.preprocess(param, verified)
+	}
+	if err != nil {
+		klog.Warnf("[nacos] preprocess config %s in group %s failed %v, keep the previous config", param.DataId, param.Group, err)
+		c.history.reject(configParamKey(param), err)
+		return
+	}
+	d := c.callbackParser(param)
+	if composed {
+		d.kind = JSON
+	}
+	callback(payload, d)
+	if d.rejected != nil {
+		c.history.reject(configParamKey(param), d.rejected)
+		return
+	}
+	if d.dryRun {
+		c.history.dryRun(configParamKey(param), d.diff)
+		return
+	}
+	if d.decoded {
+		c.history.record(configParamKey(param), data, verified)
+	}
+}
+
+func (c *client) render(cpc *ConfigParamConfig, t *template.Template) (string, error) {
+	var tpl bytes.Buffer
+	err := t.Execute(&tpl, cpc)
+	if err != nil {
+		return "", err
+	}
+	return tpl.String(), nil
+}
+
+// ServerConfigParam render server config parameters
+func (c *client) ServerConfigParam(cpc *ConfigParamConfig) (ConfigParam, error) {
+	return c.configParam(cpc, c.serverDataIDTemplate)
+}
+
+// ClientConfigParam render client config parameters
+func (c *client) ClientConfigParam(cpc *ConfigParamConfig) (ConfigParam, error) {
+	return c.configParam(cpc, c.clientDataIDTemplate)
+}
+
+// configParam render config parameters. All the parameters can be customized with CustomFunction.
+// ConfigParam explain:
+//  1. Type: data id format, support JSON, YAML, TOML and properties. Empty by default, which detects the format by
+//     the dataId suffix and the content. Could extend it by RegisterParser.
+//  2. Content: empty by default. Customize with CustomFunction.
+//  3. Group: DEFAULT_GROUP by default.
+//  4. ServerDataId: {{.ServerServiceName}}.{{.Category}} by default.
+//     ClientDataId: {{.ClientServiceName}}.{{.ServerServiceName}}.{{.Category}} by default.
+func (c *client) configParam(cpc *ConfigParamConfig, t *template.Template) (ConfigParam, error) {
+	param := ConfigParam{
+		Content: defaultContent,
+	}
+	var err error
+	param.DataId, err = c.render(cpc, t)
+	if err != nil {
+		return param, err
+	}
+	param.Group, err = c.render(cpc, c.groupTemplate)
+	if err != nil {
+		return param, err
+	}
+	return param, nil
+}
+
+// DeregisterConfig deregister the config.
+func (c *client) DeregisterConfig(cfg ConfigParam, uniqueID int64) error {
+	key := configParamKey(cfg)
+	klog.Debugf("deregister key %v for uniqueID %d", key, uniqueID)
+	c.handlerMutex.Lock()
+	handlers, ok := c.handlers[key]
+	if ok {
+		delete(handlers, uniqueID)
+	}
+	empty := len(handlers) == 0
+	if empty {
+		delete(c.handlers, key)
+	}
+	c.handlerMutex.Unlock()
+	if !empty {
+		return nil
+	}
+	klog.Debugf("the handlers for key %v is empty, cancel listen config from nacos", key)
+	c.history.remove(key)
+	c.updateIncludes(key, nil)
+	c.unlistenSignature(key)
+	return c.ncli.CancelListenConfig(cfg)
+}
+
+func (c *client) onChange(namespace, group, dataId, data string) {
+	key := configParam{
+		DataID: dataId,
+		Group:  group,
+	}
+	effective, version := c.history.remoteChanged(key, data)
+	if version != 0 {
+		klog.Infof("[nacos] config %v changed remotely, unpin the local version %d", key, version)
+	}
+	c.dispatch(key, namespace, effective)
+}
+
+func (c *client) dispatch(key configParam, namespace, data string) {
+	handlers := make([]callbackHandler, 0, 5)
+	c.handlerMutex.RLock()
+	for _, handler := range c.handlers[key] {
+		handlers = append(handlers, handler)
+	}
+	c.handlerMutex.RUnlock()
+
+	for _, handler := range handlers {
+		handler(namespace, key.Group, key.DataID, data)
+	}
+}
+
+// History returns the local versions of the config, from the oldest to the latest.
+func (c *client) History(param ConfigParam) []Version {
+	return c.history.list(configParamKey(param))
+}
+
+// Status returns the apply status of the config.
+func (c *client) Status(param ConfigParam) Status {
+	return c.history.status(configParamKey(param))
+}
+
+// Rollback applies an earlier version locally and pins it until the remote config changes or Unpin is called.
+// The previous pin is restored and the error is returned if the version is rejected when it's applied.
+func (c *client) Rollback(param ConfigParam, version int64) error {
+	key := configParamKey(param)
+	rejected := c.history.status(key).Rejected
+	content, previous, err := c.history.pin(key, version)
+	if err != nil {
+		return err
+	}
+	c.dispatch(key, "", content)
+	if status := c.history.status(key); status.Rejected != rejected {
+		c.history.repin(key, previous)
+		return fmt.Errorf("rollback config %v to the local version %d failed: %w", key, version, status.LastError)
+	}
+	klog.Infof("[nacos] config %v is rolled back and pinned to the local version %d", key, version)
+	return nil
+}
+
+// Unpin clears the pinned version and applies the remote config again.
+func (c *client) Unpin(param ConfigParam) error {
+	key := configParamKey(param)
+	remote, pinned := c.history.unpin(key)
+	if !pinned {
+		return nil
+	}
+	data, err := c.ncli.GetConfig(ConfigParam{DataId: param.DataId, Group: param.Group})
+	if err != nil {
+		klog.Warnf("[nacos] get config %v from nacos failed %v, apply the cached content", key, err)
+		data = remote
+	}
+	c.history.remoteChanged(key, data)
+	klog.Infof("[nacos] config %v is unpinned", key)
+	c.dispatch(key, "", data)
+	return nil
+}
+
+func (c *client) listenConfig(param ConfigParam, uniqueID int64) {
+	key := configParamKey(param)
+	klog.Debugf("register key %v for uniqueID %d", key, uniqueID)
+	c.handlerMutex.Lock()
+	handlers, ok := c.handlers[key]
+	if !ok {
+		handlers = map[int64]callbackHandler{}
+		c.handlers[key] = handlers
+	}
+	handlers[uniqueID] = param.OnChange
+	c.handlerMutex.Unlock()
+
+	if !ok {
+		klog.Debugf("the first time %v register, listen config from nacos", key)
+		err := c.ncli.ListenConfig(ConfigParam{
+			DataId:   param.DataId,
+			Group:    param.Group,
+			Content:  param.Content,
+			DatumId:  param.DatumId,
+			Type:     param.Type,
+			OnChange: c.onChange,
+			Native:   param.Native,
+		})
+		// Performs only local connection and fails only when the input params are invalid
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+// RegisterConfigCallback register the callback function to nacos client.
+func (c *client) RegisterConfigCallback(param ConfigParam,
+	callback func(string, ConfigParser), uniqueID int64,
+) {
+	param.OnChange = func(namespace, group, dataId, data string) {
+		klog.Debugf("[nacos] uniqueID %d config %s updated, namespace %s group %s dataId %s data %s",
+			uniqueID, param.DataId, namespace, group, dataId, data)
+		c.deliver(param, data, callback)
+	}
+
+	// NOTE: does not ensure that GetConfig succeeds, the govern policy may not be correct if it fails here.
+	data, err := c.ncli.GetConfig(param)
+	if err != nil {
+		// If the initial connection fails and the reconnection is successful, the callback handler can also be invoked.
+		// Ignore the error here and print the error info.
+		klog.Warnf("get config %v from nacos failed %v", param, err)
+	}
+
+	c.deliver(param, c.history.effective(configParamKey(param), data), callback)
+
+	c.listenConfig(param, uniqueID)
+	c.listenSignature(configParamKey(param))
+}
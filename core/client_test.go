@@ -0,0 +1,177 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeNacos struct {
+	sync.RWMutex
+	handlers map[configParam]callbackHandler
+	configs  map[configParam]string
+}
+
+func (fn *fakeNacos) GetConfig(param ConfigParam) (string, error) {
+	fn.RLock()
+	defer fn.RUnlock()
+	return fn.configs[configParamKey(param)], nil
+}
+
+func (fn *fakeNacos) ListenConfig(params ConfigParam) (err error) {
+	fn.Lock()
+	defer fn.Unlock()
+	fn.handlers[configParamKey(params)] = params.OnChange
+	return nil
+}
+
+func (fn *fakeNacos) CancelListenConfig(params ConfigParam) (err error) {
+	fn.Lock()
+	defer fn.Unlock()
+	delete(fn.handlers, configParamKey(params))
+	return nil
+}
+
+func (fn *fakeNacos) change(cfg configParam, data string) {
+	fn.RLock()
+	handler, ok := fn.handlers[cfg]
+	fn.RUnlock()
+	if !ok {
+		return
+	}
+	fmt.Println("find handers ", fn.handlers, "cfg ", cfg, " data", data)
+	handler("", cfg.Group, cfg.DataID, data)
+}
+
+func TestRegisterAndDeRegister(t *testing.T) {
+	fake := &fakeNacos{
+		handlers: map[configParam]callbackHandler{},
+	}
+	c := &client{
+		ncli:     fake,
+		handlers: map[configParam]map[int64]callbackHandler{},
+	}
+
+	var gotlock sync.Mutex
+	gots := make(map[configParam]map[int64]string)
+	key := configParam{
+		Group:  "g1",
+		DataID: "d1",
+	}
+
+	id1 := GetUniqueID()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		// register
+		c.RegisterConfigCallback(ConfigParam{
+			DataId: "d1",
+			Group:  "g1",
+		}, func(s string, cp ConfigParser) {
+			gotlock.Lock()
+			defer gotlock.Unlock()
+			ids, ok := gots[key]
+			if !ok {
+				ids = map[int64]string{}
+				gots[key] = ids
+			}
+			ids[id1] = s
+		}, id1)
+	}()
+
+	id2 := GetUniqueID()
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		c.RegisterConfigCallback(ConfigParam{
+			DataId: "d1",
+			Group:  "g1",
+		}, func(s string, cp ConfigParser) {
+			gotlock.Lock()
+			defer gotlock.Unlock()
+			ids, ok := gots[key]
+			if !ok {
+				ids = map[int64]string{}
+				gots[key] = ids
+			}
+			ids[id2] = s
+		}, id2)
+	}()
+	wg.Wait()
+
+	// first change
+	fake.change(configParam{
+		DataID: "d1",
+		Group:  "g1",
+	}, "first change")
+
+	assert.Equal(t, map[configParam]map[int64]string{
+		{
+			Group:  "g1",
+			DataID: "d1",
+		}: {
+			id1: "first change",
+			id2: "first change",
+		},
+	}, gots)
+
+	// second change
+	c.DeregisterConfig(ConfigParam{
+		DataId: "d1",
+		Group:  "g1",
+	}, id2)
+
+	fake.change(configParam{
+		DataID: "d1",
+		Group:  "g1",
+	}, "second change")
+
+	assert.Equal(t, map[configParam]map[int64]string{
+		{
+			Group:  "g1",
+			DataID: "d1",
+		}: {
+			id1: "second change",
+			id2: "first change",
+		},
+	}, gots)
+
+	// third change
+	c.DeregisterConfig(ConfigParam{
+		DataId: "d1",
+		Group:  "g1",
+	}, id1)
+
+	fake.change(configParam{
+		DataID: "d1",
+		Group:  "g1",
+	}, "third change")
+
+	assert.Equal(t, map[configParam]map[int64]string{
+		{
+			Group:  "g1",
+			DataID: "d1",
+		}: {
+			id1: "second change",
+			id2: "first change",
+		},
+	}, gots)
+}
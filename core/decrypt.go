@@ -0,0 +1,209 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// encryptedPattern matches the encrypted value: ENC(<keyID>:<base64 ciphertext>).
+var encryptedPattern = regexp.MustCompile(`^ENC\(([A-Za-z0-9._-]+):([A-Za-z0-9+/=]+)\)$`)
+
+// Decryptor decrypts the ciphertext with the key of keyID. Implement it to integrate an external KMS.
+type Decryptor interface {
+	Decrypt(keyID string, ciphertext []byte) ([]byte, error)
+}
+
+// DecryptorFunc is an adapter to allow the use of ordinary functions as Decryptor.
+type DecryptorFunc func(keyID string, ciphertext []byte) ([]byte, error)
+
+// Decrypt calls f(keyID, ciphertext).
+func (f DecryptorFunc) Decrypt(keyID string, ciphertext []byte) ([]byte, error) {
+	return f(keyID, ciphertext)
+}
+
+var _ ConfigParser = &decryptParser{}
+
+// decryptParser decrypts the whole payload or the field values in the form of ENC(...) before decoding.
+type decryptParser struct {
+	parser    ConfigParser
+	decryptor Decryptor
+}
+
+// Decode decrypts the data and decodes it with the underlying parser.
+func (p *decryptParser) Decode(kind, data string, config interface{}) error {
+	if value, ok, err := p.decrypt(strings.TrimSpace(data)); ok {
+		if err != nil {
+			return err
+		}
+		data = value
+	}
+	if !strings.Contains(data, "ENC(") {
+		return p.parser.Decode(kind, data, config)
+	}
+
+	var tree interface{}
+	if err := p.parser.Decode(kind, data, &tree); err != nil {
+		return err
+	}
+	tree, err := p.decryptTree(tree)
+	if err != nil {
+		return err
+	}
+	buf, err := json.Marshal(tree)
+	if err != nil {
+		return err
+	}
+	return p.parser.Decode(JSON, string(buf), config)
+}
+
+// decrypt returns false if the value is not encrypted.
+func (p *decryptParser) decrypt(value string) (string, bool, error) {
+	matches := encryptedPattern.FindStringSubmatch(value)
+	if matches == nil {
+		return value, false, nil
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(matches[2])
+	if err != nil {
+		return "", true, fmt.Errorf("decode ciphertext of key %s failed: %w", matches[1], err)
+	}
+	plaintext, err := p.decryptor.Decrypt(matches[1], ciphertext)
+	if err != nil {
+		return "", true, fmt.Errorf("decrypt with key %s failed: %w", matches[1], err)
+	}
+	return string(plaintext), true, nil
+}
+
+func (p *decryptParser) decryptTree(node interface{}) (interface{}, error) {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			decrypted, err := p.decryptTree(child)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", k, err)
+			}
+			v[k] = decrypted
+		}
+	case []interface{}:
+		for i, child := range v {
+			decrypted, err := p.decryptTree(child)
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %w", i, err)
+			}
+			v[i] = decrypted
+		}
+	case string:
+		value, _, err := p.decrypt(v)
+		return value, err
+	}
+	return node, nil
+}
+
+type aesKey struct {
+	aead    cipher.AEAD
+	modTime time.Time
+}
+
+// AESGCMDecryptor decrypts with AES-GCM, the key of keyID is read from the file `<dir>/<keyID>.key`
+// which contains the base64 encoded 16, 24 or 32 bytes key. The key files are reloaded once they are
+// modified, so keys can be rotated by adding new key ids without restarting.
+type AESGCMDecryptor struct {
+	dir  string
+	lock sync.Mutex
+	keys map[string]*aesKey
+}
+
+// NewAESGCMDecryptor creates a AESGCMDecryptor with the keys in dir.
+func NewAESGCMDecryptor(dir string) *AESGCMDecryptor {
+	return &AESGCMDecryptor{
+		dir:  dir,
+		keys: map[string]*aesKey{},
+	}
+}
+
+// Decrypt implements Decryptor, the ciphertext is the nonce followed by the sealed data.
+func (d *AESGCMDecryptor) Decrypt(keyID string, ciphertext []byte) ([]byte, error) {
+	aead, err := d.load(keyID)
+	if err != nil {
+		return nil, err
+	}
+	size := aead.NonceSize()
+	if len(ciphertext) < size {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+	return aead.Open(nil, ciphertext[:size], ciphertext[size:], nil)
+}
+
+func (d *AESGCMDecryptor) load(keyID string) (cipher.AEAD, error) {
+	path := filepath.Join(d.dir, keyID+".key")
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if key, ok := d.keys[keyID]; ok && key.modTime.Equal(info.ModTime()) {
+		return key.aead, nil
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(raw)))
+	if err != nil {
+		return nil, fmt.Errorf("decode key %s failed: %w", keyID, err)
+	}
+	aead, err := newAESGCM(key)
+	if err != nil {
+		return nil, fmt.Errorf("load key %s failed: %w", keyID, err)
+	}
+	d.keys[keyID] = &aesKey{aead: aead, modTime: info.ModTime()}
+	return aead, nil
+}
+
+func newAESGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// EncryptAESGCM encrypts the plaintext into ENC(<keyID>:<base64 ciphertext>), which can be
+// decrypted by AESGCMDecryptor. It's used by the tools publishing the encrypted configs.
+func EncryptAESGCM(keyID string, key, plaintext []byte) (string, error) {
+	aead, err := newAESGCM(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, plaintext, nil)
+	return fmt.Sprintf("ENC(%s:%s)", keyID, base64.StdEncoding.EncodeToString(sealed)), nil
+}
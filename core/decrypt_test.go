@@ -0,0 +1,97 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type secret struct {
+	User     string `json:"user"`
+	Password string `json:"password"`
+}
+
+func TestDecryptParser(t *testing.T) {
+	dir := t.TempDir()
+	key1 := []byte("0123456789abcdef0123456789abcdef")
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "k1.key"), []byte(base64.StdEncoding.EncodeToString(key1)), 0o600))
+	p := &decryptParser{parser: defaultConfigParse(), decryptor: NewAESGCMDecryptor(dir)}
+
+	// whole payload
+	whole, err := EncryptAESGCM("k1", key1, []byte(`{"user": "u1", "password": "p1"}`))
+	assert.Nil(t, err)
+	got := secret{}
+	assert.Nil(t, p.Decode(JSON, whole, &got))
+	assert.Equal(t, secret{User: "u1", Password: "p1"}, got)
+
+	// field values
+	field, err := EncryptAESGCM("k1", key1, []byte(`p"2`))
+	assert.Nil(t, err)
+	got = secret{}
+	assert.Nil(t, p.Decode(YAML, fmt.Sprintf("user: u2\npassword: %s\n", field), &got))
+	assert.Equal(t, secret{User: "u2", Password: `p"2`}, got)
+
+	// rotate by a new key id without restarting
+	key2 := []byte("fedcba9876543210")
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "k2.key"), []byte(base64.StdEncoding.EncodeToString(key2)), 0o600))
+	field, err = EncryptAESGCM("k2", key2, []byte("p3"))
+	assert.Nil(t, err)
+	got = secret{}
+	assert.Nil(t, p.Decode(JSON, fmt.Sprintf(`{"user": "u3", "password": "%s"}`, field), &got))
+	assert.Equal(t, secret{User: "u3", Password: "p3"}, got)
+
+	// the modified key file is reloaded
+	key3 := []byte("0000000000000000")
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "k2.key"), []byte(base64.StdEncoding.EncodeToString(key3)), 0o600))
+	later := time.Now().Add(time.Second)
+	assert.Nil(t, os.Chtimes(filepath.Join(dir, "k2.key"), later, later))
+	assert.NotNil(t, p.Decode(JSON, fmt.Sprintf(`{"password": "%s"}`, field), &got))
+	field, err = EncryptAESGCM("k2", key3, []byte("p4"))
+	assert.Nil(t, err)
+	assert.Nil(t, p.Decode(JSON, fmt.Sprintf(`{"password": "%s"}`, field), &got))
+	assert.Equal(t, "p4", got.Password)
+
+	// unknown key id
+	assert.NotNil(t, p.Decode(JSON, `{"password": "ENC(k3:AAAA)"}`, &got))
+}
+
+func TestDecryptBeforeInterpolate(t *testing.T) {
+	dir := t.TempDir()
+	key := []byte("0123456789abcdef0123456789abcdef")
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "k1.key"), []byte(base64.StdEncoding.EncodeToString(key)), 0o600))
+	t.Setenv("TEST_PASSWORD", "p1")
+	c := &client{
+		parser:       defaultConfigParse(),
+		instance:     &Instance{},
+		decryptor:    NewAESGCMDecryptor(dir),
+		interpolator: &interpolator{instance: &Instance{}},
+	}
+	// the yaml payload without the dataId suffix is detected on the plaintext
+	param := ConfigParam{DataId: "d1", Group: "g1"}
+	whole, err := EncryptAESGCM("k1", key, []byte("user: u1\npassword: ${TEST_PASSWORD}\n"))
+	assert.Nil(t, err)
+	payload, _, err := c.preprocess(param, whole)
+	assert.Nil(t, err)
+	got := secret{}
+	assert.Nil(t, c.callbackParser(param).Decode(JSON, payload, &got))
+	assert.Equal(t, secret{User: "u1", Password: "p1"}, got)
+}
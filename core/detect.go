@@ -0,0 +1,96 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"encoding/json"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// the config types of the dataId suffixes.
+var suffixKinds = map[string]string{
+	".json":       JSON,
+	".yaml":       YAML,
+	".yml":        YAML,
+	".toml":       TOML,
+	".properties": PROPERTIES,
+}
+
+var (
+	tomlTable = regexp.MustCompile(`^\[\[?[^\[\]]+\]\]?\s*(#.*)?$`)
+	yamlLine  = regexp.MustCompile(`^(-(\s|$)|---|[^=:\s]+\s*:(\s|$))`)
+)
+
+// DetectKind detects the config type by the dataId suffix, then by the content. It's used when
+// the type of the config param is empty or text.
+func DetectKind(dataID, data string) string {
+	if kind, ok := suffixKinds[strings.ToLower(path.Ext(dataID))]; ok {
+		return kind
+	}
+	return sniffKind(data)
+}
+
+// sniffKind guesses the config type by the first significant line, json by default. The content
+// starting with a brace or a bracket other than a toml table is json even if it's malformed, so
+// the json syntax error is reported instead of a misleading properties one.
+func sniffKind(data string) string {
+	trimmed := strings.TrimSpace(data)
+	if trimmed == "" {
+		return JSON
+	}
+	switch trimmed[0] {
+	case '{':
+		return JSON
+	case '[':
+		first, _, _ := strings.Cut(trimmed, "\n")
+		if json.Valid([]byte(trimmed)) || !tomlTable.MatchString(strings.TrimSpace(first)) {
+			return JSON
+		}
+	}
+	for _, line := range strings.Split(trimmed, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+		switch {
+		case tomlTable.MatchString(line):
+			return TOML
+		case yamlLine.MatchString(line):
+			return YAML
+		case strings.Contains(line, `= "`) || strings.Contains(line, `= '`):
+			// the quoted strings are required by toml, the properties values are not quoted
+			return TOML
+		default:
+			return PROPERTIES
+		}
+	}
+	return JSON
+}
+
+// kindParser resolves the config type before decoding if the type is not specified.
+type kindParser struct {
+	parser ConfigParser
+	dataID string
+}
+
+// Decode implements ConfigParser.
+func (p *kindParser) Decode(kind, data string, config interface{}) error {
+	if kind == "" || kind == TEXT {
+		kind = DetectKind(p.dataID, data)
+	}
+	return p.parser.Decode(kind, data, config)
+}
@@ -0,0 +1,95 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectKind(t *testing.T) {
+	assert.Equal(t, YAML, DetectKind("svc.retry.yml", `{"a": 1}`))
+	assert.Equal(t, TOML, DetectKind("svc.retry.TOML", ``))
+	assert.Equal(t, PROPERTIES, DetectKind("svc.retry.properties", ``))
+	assert.Equal(t, JSON, DetectKind("svc.retry.json", `a: 1`))
+
+	assert.Equal(t, JSON, DetectKind("svc.retry", ``))
+	assert.Equal(t, JSON, DetectKind("svc.retry", ` {"*": {"enable": true}}`))
+	assert.Equal(t, JSON, DetectKind("svc.retry", `[1, 2]`))
+	assert.Equal(t, JSON, DetectKind("svc.retry", `{"*": {"enable": true,}}`))
+	assert.Equal(t, JSON, DetectKind("svc.retry", "[1,\n2"))
+	assert.Equal(t, YAML, DetectKind("svc.retry", "# comment\n\"*\":\n  enable: true"))
+	assert.Equal(t, YAML, DetectKind("svc.retry", "---\na: 1"))
+	assert.Equal(t, YAML, DetectKind("svc.retry", "- a"))
+	assert.Equal(t, TOML, DetectKind("svc.retry", "[\"*\"]\nenable = true"))
+	assert.Equal(t, TOML, DetectKind("svc.retry", "[[items]]\nname = 'a'"))
+	assert.Equal(t, TOML, DetectKind("svc.retry", `backoff_type = "fixed"`))
+	assert.Equal(t, PROPERTIES, DetectKind("svc.retry", "! comment\n*.enable=true"))
+	assert.Equal(t, PROPERTIES, DetectKind("svc.retry", "a.b = fixed"))
+
+	// the malformed json reports the json syntax error
+	p := &kindParser{parser: defaultConfigParse(), dataID: "svc.limit"}
+	assert.NotNil(t, p.Decode("", `{"qps": 1`, &limit{}))
+}
+
+func TestRegisterParser(t *testing.T) {
+	const csv string = "csv"
+	RegisterParser(csv, ConfigParserFunc(func(kind, data string, config interface{}) error {
+		fields := strings.Split(data, ",")
+		*config.(*[]string) = fields
+		return nil
+	}))
+	defer func() {
+		parsersLock.Lock()
+		delete(parsers, csv)
+		parsersLock.Unlock()
+	}()
+
+	p := defaultConfigParse()
+	got := []string{}
+	assert.Nil(t, p.Decode(csv, "a,b", &got))
+	assert.Equal(t, []string{"a", "b"}, got)
+	assert.NotNil(t, p.Decode("xml", "<a/>", &got))
+}
+
+func TestMixedFormats(t *testing.T) {
+	fake := &fakeNacos{
+		handlers: map[configParam]callbackHandler{},
+	}
+	c := &client{
+		ncli:     fake,
+		parser:   defaultConfigParse(),
+		instance: &Instance{},
+		handlers: map[configParam]map[int64]callbackHandler{},
+	}
+
+	for dataID, data := range map[string]string{
+		"svc.limit":            `{"qps": 1}`,
+		"svc.limit.yaml":       "qps: 1",
+		"svc.limit.toml":       "qps = 1",
+		"svc.limit.properties": "qps=1",
+		"svc.limit.sniffed":    "qps: 1",
+	} {
+		param := ConfigParam{DataId: dataID, Group: "g1"}
+		var got limit
+		c.RegisterConfigCallback(param, func(data string, parser ConfigParser) {
+			assert.Nil(t, parser.Decode(param.Type, data, &got))
+		}, GetUniqueID())
+		fake.change(configParamKey(param), data)
+		assert.Equal(t, limit{QPS: 1}, got, dataID)
+	}
+}
@@ -0,0 +1,232 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Version a config content which has been decoded and applied locally.
+type Version struct {
+	Version   int64
+	Hash      string
+	Timestamp time.Time
+	Content   string
+	// Pinned the version is restored by Rollback and overrides the remote config.
+	Pinned bool
+
+	// payload the verified payload, so the version is not verified again against the current signature
+	// when it's rolled back.
+	payload string
+}
+
+type versionHistory struct {
+	versions []*Version
+	next     int64
+	pinned   *Version
+	// remote the latest content from nacos, it is applied again when the pin is cleared.
+	remote string
+
+	applied        uint64
+	rejected       uint64
+	lastError      error
+	lastRejectedAt time.Time
+	lastDryRunDiff []string
+	lastDryRunAt   time.Time
+}
+
+func (vh *versionHistory) latest() *Version {
+	if len(vh.versions) == 0 {
+		return nil
+	}
+	return vh.versions[len(vh.versions)-1]
+}
+
+// history keeps the last versions of every subscription, the zero value is ready to use.
+type history struct {
+	sync.Mutex
+	size  int
+	items map[configParam]*versionHistory
+}
+
+func (h *history) get(key configParam) *versionHistory {
+	if h.items == nil {
+		h.items = map[configParam]*versionHistory{}
+	}
+	vh, ok := h.items[key]
+	if !ok {
+		vh = &versionHistory{}
+		h.items[key] = vh
+	}
+	return vh
+}
+
+func contentHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// record adds the content and its verified payload as a new version. Empty content is skipped, and so are
+// contents equal to the latest or the pinned version, as they are delivered to several callbacks or replayed by Rollback.
+func (h *history) record(key configParam, content, payload string) {
+	h.Lock()
+	defer h.Unlock()
+	vh := h.get(key)
+	vh.applied++
+	if content == "" {
+		return
+	}
+	hash := contentHash(content)
+	if latest := vh.latest(); latest != nil && latest.Hash == hash {
+		return
+	}
+	if vh.pinned != nil && vh.pinned.Hash == hash {
+		return
+	}
+	vh.next++
+	vh.versions = append(vh.versions, &Version{
+		Version:   vh.next,
+		Hash:      hash,
+		Timestamp: time.Now(),
+		Content:   content,
+		payload:   payload,
+	})
+	size := h.size
+	if size <= 0 {
+		size = NacosDefaultHistorySize
+	}
+	if len(vh.versions) > size {
+		vh.versions = vh.versions[len(vh.versions)-size:]
+	}
+}
+
+// remoteChanged stores the latest remote content and clears the pin if the content changed, it returns
+// the content should be applied, which is still the pinned version if the same content is redelivered.
+func (h *history) remoteChanged(key configParam, content string) (effective string, unpinned int64) {
+	h.Lock()
+	defer h.Unlock()
+	vh := h.get(key)
+	changed := vh.remote != content
+	vh.remote = content
+	if vh.pinned == nil {
+		return content, 0
+	}
+	if !changed {
+		return vh.pinned.Content, 0
+	}
+	unpinned = vh.pinned.Version
+	vh.pinned = nil
+	return content, unpinned
+}
+
+// effective returns the content should be applied, which is the pinned version if exists.
+func (h *history) effective(key configParam, remote string) string {
+	h.Lock()
+	defer h.Unlock()
+	vh := h.get(key)
+	vh.remote = remote
+	if vh.pinned != nil {
+		return vh.pinned.Content
+	}
+	return remote
+}
+
+// current returns the content applied currently, which is the pinned version or the latest remote content.
+func (h *history) current(key configParam) string {
+	h.Lock()
+	defer h.Unlock()
+	vh := h.get(key)
+	if vh.pinned != nil {
+		return vh.pinned.Content
+	}
+	return vh.remote
+}
+
+// verified returns the verified payload if the content is the pinned version, which has been verified
+// when it was applied.
+func (h *history) verified(key configParam, content string) (string, bool) {
+	h.Lock()
+	defer h.Unlock()
+	vh, ok := h.items[key]
+	if !ok || vh.pinned == nil || vh.pinned.Content != content {
+		return "", false
+	}
+	return vh.pinned.payload, true
+}
+
+// pin pins the version and returns its content and the version pinned previously, 0 if not pinned.
+func (h *history) pin(key configParam, version int64) (content string, previous int64, err error) {
+	h.Lock()
+	defer h.Unlock()
+	vh := h.get(key)
+	if vh.pinned != nil {
+		previous = vh.pinned.Version
+	}
+	for _, v := range vh.versions {
+		if v.Version == version {
+			vh.pinned = v
+			return v.Content, previous, nil
+		}
+	}
+	return "", previous, fmt.Errorf("version %d of config %v not found in the local history", version, key)
+}
+
+// repin restores the version pinned previously, the pin is cleared if the version is 0 or not found.
+func (h *history) repin(key configParam, version int64) {
+	h.Lock()
+	defer h.Unlock()
+	vh := h.get(key)
+	vh.pinned = nil
+	for _, v := range vh.versions {
+		if v.Version == version {
+			vh.pinned = v
+		}
+	}
+}
+
+func (h *history) unpin(key configParam) (remote string, pinned bool) {
+	h.Lock()
+	defer h.Unlock()
+	vh := h.get(key)
+	pinned = vh.pinned != nil
+	vh.pinned = nil
+	return vh.remote, pinned
+}
+
+func (h *history) list(key configParam) []Version {
+	h.Lock()
+	defer h.Unlock()
+	vh, ok := h.items[key]
+	if !ok {
+		return nil
+	}
+	out := make([]Version, 0, len(vh.versions))
+	for _, v := range vh.versions {
+		version := *v
+		version.Pinned = v == vh.pinned
+		out = append(out, version)
+	}
+	return out
+}
+
+func (h *history) remove(key configParam) {
+	h.Lock()
+	defer h.Unlock()
+	delete(h.items, key)
+}
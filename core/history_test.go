@@ -0,0 +1,187 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistoryAndRollback(t *testing.T) {
+	fake := &fakeNacos{
+		handlers: map[configParam]callbackHandler{},
+	}
+	c := &client{
+		ncli:     fake,
+		parser:   defaultConfigParse(),
+		instance: &Instance{},
+		handlers: map[configParam]map[int64]callbackHandler{},
+		history:  history{size: 2},
+	}
+	param := ConfigParam{DataId: "d1", Group: "g1", Type: JSON}
+	key := configParamKey(param)
+
+	var applied limit
+	c.RegisterConfigCallback(param, func(data string, parser ConfigParser) {
+		got := limit{}
+		if err := parser.Decode(param.Type, data, &got); err != nil {
+			return
+		}
+		applied = got
+	}, GetUniqueID())
+
+	fake.change(key, `{"qps": 1}`)
+	fake.change(key, `{"qps": 2}`)
+	fake.change(key, `invalid`)
+	status := c.Status(param)
+	assert.Equal(t, int64(2), status.Version)
+	assert.Equal(t, uint64(3), status.Applied)
+	assert.Equal(t, uint64(1), status.Rejected)
+	assert.NotNil(t, status.LastError)
+	versions := c.History(param)
+	assert.Equal(t, 2, len(versions))
+	assert.Equal(t, `{"qps": 1}`, versions[0].Content)
+	assert.Equal(t, `{"qps": 2}`, versions[1].Content)
+	assert.Equal(t, contentHash(`{"qps": 2}`), versions[1].Hash)
+
+	// the invalid content is not recorded and the history is limited by size
+	fake.change(key, `{"qps": 3}`)
+	versions = c.History(param)
+	assert.Equal(t, []int64{2, 3}, []int64{versions[0].Version, versions[1].Version})
+
+	assert.NotNil(t, c.Rollback(param, 1))
+	assert.Nil(t, c.Rollback(param, 2))
+	assert.Equal(t, limit{QPS: 2}, applied)
+	versions = c.History(param)
+	assert.Equal(t, 2, len(versions))
+	assert.True(t, versions[0].Pinned)
+	assert.Equal(t, int64(2), c.Status(param).Pinned)
+
+	// the redelivered remote content keeps the pinned version
+	fake.change(key, `{"qps": 3}`)
+	assert.Equal(t, limit{QPS: 2}, applied)
+	assert.Equal(t, int64(2), c.Status(param).Pinned)
+
+	// a new registration applies the pinned version
+	var applied2 limit
+	c.RegisterConfigCallback(param, func(data string, parser ConfigParser) {
+		parser.Decode(param.Type, data, &applied2)
+	}, GetUniqueID())
+	assert.Equal(t, limit{QPS: 2}, applied2)
+
+	// unpin applies the remote config
+	assert.Nil(t, c.Unpin(param))
+	assert.Equal(t, limit{QPS: 0}, applied)
+
+	// the remote change clears the pin
+	assert.Nil(t, c.Rollback(param, 3))
+	fake.change(key, `{"qps": 4}`)
+	assert.Equal(t, limit{QPS: 4}, applied)
+	for _, v := range c.History(param) {
+		assert.False(t, v.Pinned)
+	}
+}
+
+func TestRollbackSigned(t *testing.T) {
+	secret := []byte("secret")
+	fake := &fakeNacos{
+		handlers: map[configParam]callbackHandler{},
+		configs:  map[configParam]string{},
+	}
+	c := &client{
+		ncli:            fake,
+		parser:          defaultConfigParse(),
+		instance:        &Instance{},
+		handlers:        map[configParam]map[int64]callbackHandler{},
+		verifier:        NewKeySetVerifier(TrustedKey{ID: "mac", Algorithm: SignatureHMACSHA256, Key: secret}),
+		signatureSuffix: NacosDefaultSignatureSuffix,
+	}
+	param := ConfigParam{DataId: "d1", Group: "g1", Type: JSON}
+	key := configParamKey(param)
+	sigKey := configParam{DataID: "d1.sig", Group: "g1"}
+	publish := func(payload string) {
+		sig, err := json.Marshal(SignHMACSHA256("mac", secret, SignedContent("g1", "d1", payload)))
+		assert.Nil(t, err)
+		fake.change(sigKey, string(sig))
+		fake.change(key, payload)
+	}
+
+	var applied limit
+	reject := 0
+	c.RegisterConfigCallback(param, func(data string, parser ConfigParser) {
+		got := limit{}
+		if err := parser.Decode(param.Type, data, &got); err != nil {
+			return
+		}
+		if got.QPS == reject {
+			Reject(parser, errors.New("rejected"))
+			return
+		}
+		applied = got
+	}, GetUniqueID())
+
+	publish(`{"qps": 1}`)
+	publish(`{"qps": 2}`)
+	publish(`{"qps": 3}`)
+	assert.Equal(t, limit{QPS: 3}, applied)
+
+	// the version verified locally is applied although the detached signature is for the latest one
+	assert.Nil(t, c.Rollback(param, 2))
+	assert.Equal(t, limit{QPS: 2}, applied)
+	assert.Equal(t, int64(2), c.Status(param).Pinned)
+
+	// the rejected version returns the error and keeps the previous pin
+	reject = 1
+	assert.EqualError(t, c.Rollback(param, 1), "rollback config {d1 g1} to the local version 1 failed: rejected")
+	assert.Equal(t, limit{QPS: 2}, applied)
+	assert.Equal(t, int64(2), c.Status(param).Pinned)
+
+	// the remote content is verified as usual
+	reject = 0
+	fake.change(key, `{"qps": 1}`)
+	assert.EqualError(t, c.Status(param).LastError, "invalid signature of key mac")
+	assert.Equal(t, limit{QPS: 2}, applied)
+}
+
+func TestDryRunStatus(t *testing.T) {
+	fake := &fakeNacos{
+		handlers: map[configParam]callbackHandler{},
+	}
+	c := &client{
+		ncli:     fake,
+		parser:   defaultConfigParse(),
+		instance: &Instance{},
+		handlers: map[configParam]map[int64]callbackHandler{},
+	}
+	param := ConfigParam{DataId: "d1", Group: "g1", Type: JSON}
+
+	c.RegisterConfigCallback(param, func(data string, parser ConfigParser) {
+		got := limit{}
+		if err := parser.Decode(param.Type, data, &got); err != nil {
+			return
+		}
+		DryRun(parser, []string{"~ qps: 0 -> 1"})
+	}, GetUniqueID())
+
+	fake.change(configParamKey(param), `{"qps": 1}`)
+	status := c.Status(param)
+	assert.Equal(t, []string{"~ qps: 0 -> 1"}, status.LastDryRunDiff)
+	assert.False(t, status.LastDryRunAt.IsZero())
+	assert.Equal(t, int64(0), status.Version)
+	assert.Empty(t, c.History(param))
+}
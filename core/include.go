@@ -0,0 +1,267 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/cloudwego/kitex/pkg/klog"
+)
+
+// IncludeKey the key of the include directive, the value is a dataId, an object with dataId and group,
+// or a list of them. The group of the including config is used if the group is empty.
+const IncludeKey = "$include"
+
+type includeRef struct {
+	DataID string `json:"dataId"`
+	Group  string `json:"group"`
+}
+
+// includes tracks the included dataIds of every subscription, the zero value is ready to use.
+type includes struct {
+	sync.Mutex
+	// id the unique id to listen the included dataIds
+	id       int64
+	contents map[configParam]string
+	// deps the included dataIds of every subscription, including the nested ones
+	deps map[configParam]map[configParam]bool
+}
+
+func (in *includes) init() {
+	if in.id == 0 {
+		in.id = GetUniqueID()
+		in.contents = map[configParam]string{}
+		in.deps = map[configParam]map[configParam]bool{}
+	}
+}
+
+// dependents returns the subscriptions including the key, the caller must hold the lock.
+func (in *includes) dependents(key configParam) []configParam {
+	var out []configParam
+	for top, deps := range in.deps {
+		if deps[key] {
+			out = append(out, top)
+		}
+	}
+	return out
+}
+
+// compose resolves the include directives of the data into a json document, false if nothing is included.
+func (c *client) compose(param ConfigParam, data string) (string, bool, error) {
+	key := configParamKey(param)
+	if !strings.Contains(data, IncludeKey) {
+		c.updateIncludes(key, nil)
+		return data, false, nil
+	}
+	deps := map[configParam]bool{}
+	tree, included, err := c.resolveIncludes(key, param.Type, data, []configParam{key}, deps)
+	// listen the included dataIds even if it fails, so the fix of them is delivered again
+	c.updateIncludes(key, deps)
+	if err != nil || !included {
+		return data, false, err
+	}
+	buf, err := json.Marshal(tree)
+	if err != nil {
+		return "", false, err
+	}
+	return string(buf), true, nil
+}
+
+// resolveIncludes merges the included documents in order, and then the including document itself.
+func (c *client) resolveIncludes(key configParam, kind, data string,
+	stack []configParam, deps map[configParam]bool,
+) (interface{}, bool, error) {
+	if kind == "" || kind == TEXT {
+		kind = DetectKind(key.DataID, data)
+	}
+	var tree interface{}
+	if err := c.parser.Decode(kind, data, &tree); err != nil {
+		return nil, false, err
+	}
+	doc, ok := tree.(map[string]interface{})
+	if !ok || doc[IncludeKey] == nil {
+		return tree, false, nil
+	}
+	refs, err := includeRefs(doc[IncludeKey], key.Group)
+	if err != nil {
+		return nil, false, err
+	}
+	delete(doc, IncludeKey)
+
+	var merged interface{} = map[string]interface{}{}
+	for _, ref := range refs {
+		path := append(stack[:len(stack):len(stack)], ref)
+		for _, k := range stack {
+			if k == ref {
+				return nil, false, fmt.Errorf("include cycle: %s", includePath(path))
+			}
+		}
+		deps[ref] = true
+		content, err := c.includedContent(ref)
+		var sub interface{}
+		if err == nil {
+			sub, _, err = c.resolveIncludes(ref, "", content, path, deps)
+		}
+		if err != nil {
+			return nil, false, fmt.Errorf("include %s/%s: %w", ref.Group, ref.DataID, err)
+		}
+		merged = mergeTree(merged, sub)
+	}
+	return mergeTree(merged, doc), true, nil
+}
+
+func includeRefs(value interface{}, group string) ([]configParam, error) {
+	items, ok := value.([]interface{})
+	if !ok {
+		items = []interface{}{value}
+	}
+	refs := make([]configParam, 0, len(items))
+	for _, item := range items {
+		ref := includeRef{}
+		switch v := item.(type) {
+		case string:
+			ref.DataID = v
+		case map[string]interface{}:
+			buf, err := json.Marshal(v)
+			if err != nil {
+				return nil, err
+			}
+			if err = json.Unmarshal(buf, &ref); err != nil {
+				return nil, fmt.Errorf("invalid %s %v: %w", IncludeKey, v, err)
+			}
+		}
+		if ref.DataID == "" {
+			return nil, fmt.Errorf("invalid %s %v: empty dataId", IncludeKey, item)
+		}
+		if ref.Group == "" {
+			ref.Group = group
+		}
+		refs = append(refs, configParam{DataID: ref.DataID, Group: ref.Group})
+	}
+	return refs, nil
+}
+
+func includePath(path []configParam) string {
+	names := make([]string, 0, len(path))
+	for _, k := range path {
+		names = append(names, k.Group+"/"+k.DataID)
+	}
+	return strings.Join(names, " -> ")
+}
+
+// includedContent returns the verified and resolved content of the included dataId.
+func (c *client) includedContent(key configParam) (string, error) {
+	c.includes.Lock()
+	content, ok := c.includes.contents[key]
+	c.includes.Unlock()
+	if !ok {
+		var err error
+		content, err = c.ncli.GetConfig(ConfigParam{DataId: key.DataID, Group: key.Group})
+		if err != nil {
+			return "", err
+		}
+		c.includes.Lock()
+		c.includes.init()
+		c.includes.contents[key] = content
+		c.includes.Unlock()
+	}
+	if strings.TrimSpace(content) == "" {
+		return "", fmt.Errorf("config not found")
+	}
+	return c.prepare(ConfigParam{DataId: key.DataID, Group: key.Group}, content)
+}
+
+// mergeTree merges the overlay into the base recursively, the values other than objects are replaced.
+func mergeTree(base, overlay interface{}) interface{} {
+	b, ok := base.(map[string]interface{})
+	o, ok2 := overlay.(map[string]interface{})
+	if !ok || !ok2 {
+		return overlay
+	}
+	for k, v := range o {
+		b[k] = mergeTree(b[k], v)
+	}
+	return b
+}
+
+// updateIncludes replaces the included dataIds of the subscription, and listens or cancels the
+// included dataIds which are included for the first time or not included anymore.
+func (c *client) updateIncludes(key configParam, deps map[configParam]bool) {
+	c.includes.Lock()
+	if len(deps) == 0 && c.includes.deps[key] == nil {
+		c.includes.Unlock()
+		return
+	}
+	c.includes.init()
+	before := map[configParam]bool{}
+	for dep := range c.includes.deps[key] {
+		before[dep] = len(c.includes.dependents(dep)) > 0
+	}
+	for dep := range deps {
+		before[dep] = len(c.includes.dependents(dep)) > 0
+	}
+	if len(deps) == 0 {
+		delete(c.includes.deps, key)
+	} else {
+		c.includes.deps[key] = deps
+	}
+	var listen, cancel []configParam
+	for dep, included := range before {
+		after := len(c.includes.dependents(dep)) > 0
+		switch {
+		case !included && after:
+			listen = append(listen, dep)
+		case included && !after:
+			cancel = append(cancel, dep)
+			delete(c.includes.contents, dep)
+		}
+	}
+	id := c.includes.id
+	c.includes.Unlock()
+
+	for _, dep := range listen {
+		dep := dep
+		klog.Debugf("[nacos] config %v includes %v, listen it", key, dep)
+		c.listenConfig(ConfigParam{
+			DataId: dep.DataID,
+			Group:  dep.Group,
+			OnChange: func(namespace, group, dataId, data string) {
+				c.includeChanged(dep, data)
+			},
+		}, id)
+	}
+	for _, dep := range cancel {
+		klog.Debugf("[nacos] config %v is not included anymore, cancel listening it", dep)
+		if err := c.DeregisterConfig(ConfigParam{DataId: dep.DataID, Group: dep.Group}, id); err != nil {
+			klog.Warnf("[nacos] cancel listening the included config %v failed %v", dep, err)
+		}
+	}
+}
+
+// includeChanged delivers the subscriptions including the key again.
+func (c *client) includeChanged(key configParam, data string) {
+	c.includes.Lock()
+	c.includes.init()
+	c.includes.contents[key] = data
+	tops := c.includes.dependents(key)
+	c.includes.Unlock()
+	for _, top := range tops {
+		klog.Infof("[nacos] the included config %v changed, deliver config %v again", key, top)
+		c.dispatch(top, "", c.history.current(top))
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/cloudwego/kitex/pkg/rpctimeout"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInclude(t *testing.T) {
+	shared := configParam{DataID: "shared.rpc_timeout", Group: "common"}
+	base := configParam{DataID: "base.yaml", Group: "g1"}
+	fake := &fakeNacos{
+		handlers: map[configParam]callbackHandler{},
+		configs: map[configParam]string{
+			shared: `{"*": {"rpc_timeout_ms": 100, "conn_timeout_ms": 10}, "Echo": {"rpc_timeout_ms": 200}}`,
+			base:   "Echo:\n  conn_timeout_ms: 20\n",
+		},
+	}
+	c := &client{
+		ncli:     fake,
+		parser:   defaultConfigParse(),
+		instance: &Instance{},
+		handlers: map[configParam]map[int64]callbackHandler{},
+	}
+	param := ConfigParam{DataId: "svc.rpc_timeout", Group: "g1"}
+	key := configParamKey(param)
+	fake.configs[key] = `{"$include": [{"dataId": "shared.rpc_timeout", "group": "common"}, "base.yaml"],
+		"Echo": {"rpc_timeout_ms": 300}}`
+
+	var applied map[string]*rpctimeout.RPCTimeout
+	id := GetUniqueID()
+	c.RegisterConfigCallback(param, func(data string, parser ConfigParser) {
+		configs := map[string]*rpctimeout.RPCTimeout{}
+		if err := parser.Decode(param.Type, data, &configs); err != nil {
+			return
+		}
+		applied = configs
+	}, id)
+	assert.Equal(t, map[string]*rpctimeout.RPCTimeout{
+		"*":    {RPCTimeoutMS: 100, ConnTimeoutMS: 10},
+		"Echo": {RPCTimeoutMS: 300, ConnTimeoutMS: 20},
+	}, applied)
+	// the history records the content of the dataId itself
+	assert.Equal(t, fake.configs[key], c.History(param)[0].Content)
+
+	// delivered again once an included dataId changes
+	fake.change(shared, `{"*": {"rpc_timeout_ms": 150}}`)
+	assert.Equal(t, &rpctimeout.RPCTimeout{RPCTimeoutMS: 150}, applied["*"])
+	assert.Equal(t, &rpctimeout.RPCTimeout{RPCTimeoutMS: 300, ConnTimeoutMS: 20}, applied["Echo"])
+
+	// the included dataIds are not listened anymore once they are not included
+	fake.change(key, `{"$include": "base.yaml"}`)
+	assert.Equal(t, map[string]*rpctimeout.RPCTimeout{"Echo": {ConnTimeoutMS: 20}}, applied)
+	assert.NotContains(t, fake.handlers, shared)
+	assert.Contains(t, fake.handlers, base)
+	assert.Nil(t, c.DeregisterConfig(param, id))
+	assert.Empty(t, fake.handlers)
+}
+
+func TestIncludeCycle(t *testing.T) {
+	a := configParam{DataID: "a", Group: "g1"}
+	b := configParam{DataID: "b", Group: "g1"}
+	fake := &fakeNacos{
+		handlers: map[configParam]callbackHandler{},
+		configs: map[configParam]string{
+			a: `{"$include": "b"}`,
+			b: `{"$include": "a"}`,
+		},
+	}
+	c := &client{
+		ncli:     fake,
+		parser:   defaultConfigParse(),
+		instance: &Instance{},
+		handlers: map[configParam]map[int64]callbackHandler{},
+	}
+	param := ConfigParam{DataId: "svc", Group: "g1"}
+	key := configParamKey(param)
+	fake.configs[key] = `{"$include": ["a", "b"]}`
+
+	applied := 0
+	c.RegisterConfigCallback(param, func(data string, parser ConfigParser) {
+		applied++
+	}, GetUniqueID())
+	assert.Equal(t, 0, applied)
+	assert.EqualError(t, c.Status(param).LastError, "include g1/a: include g1/b: include cycle: g1/svc -> g1/a -> g1/b -> g1/a")
+
+	// fix the cycle, the included dataIds are listened even if it failed
+	fake.change(b, `{"*": {"rpc_timeout_ms": 100}}`)
+	assert.Equal(t, 1, applied)
+
+	// the diamond is not a cycle
+	_, composed, err := c.preprocess(param, `{"$include": ["a", "b"]}`)
+	assert.Nil(t, err)
+	assert.True(t, composed)
+
+	// missing included dataId
+	_, _, err = c.preprocess(param, `{"$include": "missing"}`)
+	assert.EqualError(t, err, "include g1/missing: config not found")
+}
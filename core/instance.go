@@ -0,0 +1,75 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"net"
+	"os"
+)
+
+// keep consistent with the downward api env in kubernetes
+const (
+	PodNameEnv = "POD_NAME"
+	PodIPEnv   = "POD_IP"
+)
+
+// Instance the identity of the current process, used to select the rollout branch of a config.
+type Instance struct {
+	IP     string
+	Pod    string
+	Labels map[string]string
+}
+
+// key returns the stable identity used to hash the instance into a rollout bucket.
+func (i *Instance) key() string {
+	if i.Pod == "" {
+		return i.IP
+	}
+	return i.IP + "/" + i.Pod
+}
+
+// fillDefault completes the empty fields of the instance from the environment.
+func (i *Instance) fillDefault() {
+	if i.IP == "" {
+		i.IP = localIP()
+	}
+	if i.Pod == "" {
+		i.Pod = podName()
+	}
+}
+
+func podName() string {
+	if name := os.Getenv(PodNameEnv); name != "" {
+		return name
+	}
+	name, _ := os.Hostname()
+	return name
+}
+
+func localIP() string {
+	if ip := os.Getenv(PodIPEnv); ip != "" {
+		return ip
+	}
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return ""
+	}
+	for _, addr := range addrs {
+		if ipNet, ok := addr.(*net.IPNet); ok && !ipNet.IP.IsLoopback() && ipNet.IP.To4() != nil {
+			return ipNet.IP.String()
+		}
+	}
+	return ""
+}
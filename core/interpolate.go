@@ -0,0 +1,97 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// UnresolvedPolicy decides how to handle the variables which can not be resolved.
+type UnresolvedPolicy int
+
+const (
+	// UnresolvedReject rejects the content and keeps the previous config.
+	UnresolvedReject UnresolvedPolicy = iota
+	// UnresolvedKeep keeps the placeholder as is.
+	UnresolvedKeep
+	// UnresolvedEmpty replaces the placeholder with the empty string.
+	UnresolvedEmpty
+)
+
+const (
+	labelsPrefix     = "labels."
+	instanceIPVar    = "instance.ip"
+	instancePodVar   = "instance.pod"
+	escapedDelimiter = "$${"
+)
+
+// placeholderPattern matches the escaped delimiter $${, or ${name} and ${name:-default}.
+var placeholderPattern = regexp.MustCompile(`\$\$\{|\$\{([A-Za-z_][A-Za-z0-9_.-]*)(:-([^}]*))?\}`)
+
+// interpolator resolves the placeholders in the payloads with the environment variables and the instance.
+type interpolator struct {
+	instance *Instance
+	policy   UnresolvedPolicy
+}
+
+// interpolate resolves ${ENV}, ${ENV:-default}, ${labels.<key>}, ${instance.ip} and ${instance.pod},
+// the default value is used if the variable is unset or empty. $${ is unescaped to ${ without resolving.
+func (i *interpolator) interpolate(data string) (string, error) {
+	if !strings.Contains(data, "${") {
+		return data, nil
+	}
+	var unresolved []string
+	out := placeholderPattern.ReplaceAllStringFunc(data, func(match string) string {
+		if match == escapedDelimiter {
+			return "${"
+		}
+		groups := placeholderPattern.FindStringSubmatch(match)
+		if value, ok := i.lookup(groups[1]); ok && value != "" {
+			return value
+		}
+		if groups[2] != "" {
+			return groups[3]
+		}
+		if value, ok := i.lookup(groups[1]); ok {
+			return value
+		}
+		unresolved = append(unresolved, groups[1])
+		if i.policy == UnresolvedEmpty {
+			return ""
+		}
+		return match
+	})
+	if len(unresolved) > 0 && i.policy == UnresolvedReject {
+		return "", fmt.Errorf("unresolved variables %v", unresolved)
+	}
+	return out, nil
+}
+
+func (i *interpolator) lookup(name string) (string, bool) {
+	switch {
+	case strings.HasPrefix(name, labelsPrefix):
+		value, ok := i.instance.Labels[strings.TrimPrefix(name, labelsPrefix)]
+		return value, ok
+	case name == instanceIPVar:
+		return i.instance.IP, i.instance.IP != ""
+	case name == instancePodVar:
+		return i.instance.Pod, i.instance.Pod != ""
+	default:
+		return os.LookupEnv(name)
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/cloudwego/kitex/pkg/rpctimeout"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterpolate(t *testing.T) {
+	t.Setenv("TEST_TIMEOUT", "200")
+	t.Setenv("TEST_EMPTY", "")
+	i := &interpolator{instance: &Instance{IP: "10.0.0.1", Pod: "pod-1", Labels: map[string]string{"zone": "z1"}}}
+
+	got, err := i.interpolate(`${TEST_TIMEOUT} ${TEST_UNSET:-100} ${TEST_EMPTY:-50} ${labels.zone} ${instance.ip} ${instance.pod}`)
+	assert.Nil(t, err)
+	assert.Equal(t, `200 100 50 z1 10.0.0.1 pod-1`, got)
+
+	// escape
+	got, err = i.interpolate(`$${TEST_TIMEOUT} $${labels.zone} ${TEST_UNSET:-}`)
+	assert.Nil(t, err)
+	assert.Equal(t, `${TEST_TIMEOUT} ${labels.zone} `, got)
+
+	// the empty variable without default is resolved to empty
+	got, err = i.interpolate(`[${TEST_EMPTY}]`)
+	assert.Nil(t, err)
+	assert.Equal(t, `[]`, got)
+
+	// unresolved
+	_, err = i.interpolate(`${TEST_UNSET} ${labels.region}`)
+	assert.EqualError(t, err, "unresolved variables [TEST_UNSET labels.region]")
+	i.policy = UnresolvedKeep
+	got, err = i.interpolate(`${TEST_UNSET} ${labels.region}`)
+	assert.Nil(t, err)
+	assert.Equal(t, `${TEST_UNSET} ${labels.region}`, got)
+	i.policy = UnresolvedEmpty
+	got, err = i.interpolate(`[${TEST_UNSET}]`)
+	assert.Nil(t, err)
+	assert.Equal(t, `[]`, got)
+}
+
+func TestInterpolateFormats(t *testing.T) {
+	t.Setenv("TEST_TIMEOUT", "200")
+	c := &client{
+		parser:       defaultConfigParse(),
+		instance:     &Instance{Labels: map[string]string{"zone": "z1"}},
+		interpolator: &interpolator{instance: &Instance{Labels: map[string]string{"zone": "z1"}}},
+	}
+	param := ConfigParam{DataId: "d1", Group: "g1"}
+
+	for kind, data := range map[string]string{
+		JSON:       `{"${labels.zone}.Echo": {"rpc_timeout_ms": ${TEST_TIMEOUT}, "conn_timeout_ms": ${TEST_CONN:-50}}}`,
+		YAML:       "${labels.zone}.Echo:\n  rpc_timeout_ms: ${TEST_TIMEOUT}\n  conn_timeout_ms: ${TEST_CONN:-50}\n",
+		TOML:       "[\"${labels.zone}.Echo\"]\nrpc_timeout_ms = ${TEST_TIMEOUT}\nconn_timeout_ms = ${TEST_CONN:-50}\n",
+		PROPERTIES: "${labels.zone}_Echo.rpc_timeout_ms=${TEST_TIMEOUT}\n${labels.zone}_Echo.conn_timeout_ms=${TEST_CONN:-50}\n",
+	} {
+		payload, _, err := c.preprocess(param, data)
+		assert.Nil(t, err, kind)
+		configs := map[string]*rpctimeout.RPCTimeout{}
+		assert.Nil(t, c.callbackParser(param).Decode(kind, payload, &configs), kind)
+		assert.Equal(t, 1, len(configs), kind)
+		for method, config := range configs {
+			assert.Contains(t, method, "z1", kind)
+			assert.Equal(t, &rpctimeout.RPCTimeout{RPCTimeoutMS: 200, ConnTimeoutMS: 50}, config, kind)
+		}
+	}
+}
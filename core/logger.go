@@ -0,0 +1,54 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"github.com/cloudwego/kitex/pkg/klog"
+)
+
+// NacosLogger forwards the logs of the nacos sdk to klog, it implements the logger of both the nacos sdk v1 and v2.
+type NacosLogger struct{}
+
+func (m NacosLogger) Info(args ...interface{}) {
+	klog.Info(args...)
+}
+
+func (m NacosLogger) Warn(args ...interface{}) {
+	klog.Warn(args...)
+}
+
+func (m NacosLogger) Error(args ...interface{}) {
+	klog.Error(args...)
+}
+
+func (m NacosLogger) Debug(args ...interface{}) {
+	klog.Debug(args...)
+}
+
+func (m NacosLogger) Infof(fmt string, args ...interface{}) {
+	klog.Infof(fmt, args...)
+}
+
+func (m NacosLogger) Warnf(fmt string, args ...interface{}) {
+	klog.Warnf(fmt, args...)
+}
+
+func (m NacosLogger) Errorf(fmt string, args ...interface{}) {
+	klog.Errorf(fmt, args...)
+}
+
+func (m NacosLogger) Debugf(fmt string, args ...interface{}) {
+	klog.Debugf(fmt, args...)
+}
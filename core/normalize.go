@@ -0,0 +1,162 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const durationSuffix = "_ms"
+
+var (
+	// ratioFields the fields in [0, 1], which accept the percentages like "1.5%" as well.
+	ratioFields = map[string]bool{"err_rate": true, "error_rate": true}
+	// percentFields the fields in [0, 100], which accept the percentages like "50%" as well. The numbers
+	// are always percentages, e.g. 0.5 is 0.5%. The value is whether the field is an integer.
+	percentFields = map[string]bool{"percentage": true, "max_retry_percentage": false}
+)
+
+var _ ConfigParser = &normalizeParser{}
+
+// normalizeParser converts the human-friendly values into the numbers expected by the config structs:
+// the durations like "500ms" and "2s" of the *_ms fields into milliseconds, and the percentages like
+// "1.5%" of the ratio and percentage fields. The numbers are kept as is, except that the fractional
+// percentages of the integer fields are rejected.
+type normalizeParser struct {
+	parser ConfigParser
+}
+
+// Decode implements ConfigParser.
+func (p *normalizeParser) Decode(kind, data string, config interface{}) error {
+	var tree interface{}
+	if err := p.parser.Decode(kind, data, &tree); err != nil {
+		return p.parser.Decode(kind, data, config)
+	}
+	tree, changed, err := normalize("", "", tree)
+	if err != nil {
+		return err
+	}
+	if !changed {
+		return p.parser.Decode(kind, data, config)
+	}
+	buf, err := json.Marshal(tree)
+	if err != nil {
+		return err
+	}
+	return p.parser.Decode(JSON, string(buf), config)
+}
+
+// normalize converts the value of the field recursively, and reports whether anything is converted.
+func normalize(path, field string, value interface{}) (interface{}, bool, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		changed := false
+		for k, child := range v {
+			converted, ok, err := normalize(joinPath(path, k), k, child)
+			if err != nil {
+				return nil, false, err
+			}
+			if ok {
+				v[k], changed = converted, true
+			}
+		}
+		return v, changed, nil
+	case []interface{}:
+		changed := false
+		for i, child := range v {
+			converted, ok, err := normalize(fmt.Sprintf("%s[%d]", path, i), field, child)
+			if err != nil {
+				return nil, false, err
+			}
+			if ok {
+				v[i], changed = converted, true
+			}
+		}
+		return v, changed, nil
+	case string:
+		converted, err := normalizeString(field, v)
+		if err != nil {
+			return nil, false, fmt.Errorf("%s: %w", path, err)
+		}
+		return converted, converted != nil, nil
+	case float64:
+		if err := checkPercent(field, v); err != nil {
+			return nil, false, fmt.Errorf("%s: %w", path, err)
+		}
+		return value, false, nil
+	}
+	return value, false, nil
+}
+
+// normalizeString returns nil if the field is not converted.
+func normalizeString(field, value string) (interface{}, error) {
+	s := strings.TrimSpace(value)
+	switch {
+	case strings.HasSuffix(field, durationSuffix):
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration %q", value)
+		}
+		if d%time.Millisecond != 0 {
+			return nil, fmt.Errorf("duration %q is not a whole number of milliseconds", value)
+		}
+		return int64(d / time.Millisecond), nil
+	case ratioFields[field]:
+		f, err := parsePercent(s)
+		if err != nil {
+			return nil, err
+		}
+		if strings.HasSuffix(s, "%") {
+			f /= 100
+		}
+		return f, nil
+	}
+	if _, ok := percentFields[field]; ok {
+		f, err := parsePercent(s)
+		if err != nil {
+			return nil, err
+		}
+		return f, checkPercent(field, f)
+	}
+	return nil, nil
+}
+
+// checkPercent rejects the fractional percentages of the integer fields.
+func checkPercent(field string, f float64) error {
+	if percentFields[field] && f != math.Trunc(f) {
+		return fmt.Errorf("percentage %s%% is not a whole number", strconv.FormatFloat(f, 'f', -1, 64))
+	}
+	return nil
+}
+
+func parsePercent(s string) (float64, error) {
+	f, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(s, "%")), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid percentage %q", s)
+	}
+	return f, nil
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,98 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cloudwego/kitex/pkg/circuitbreak"
+	"github.com/cloudwego/kitex/pkg/retry"
+	"github.com/cloudwego/kitex/pkg/rpctimeout"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kitex-contrib/config-nacos/pkg/degradation"
+)
+
+func TestNormalize(t *testing.T) {
+	c := &client{
+		parser:   defaultConfigParse(),
+		instance: &Instance{},
+	}
+	param := ConfigParam{DataId: "d1", Group: "g1"}
+
+	timeouts := map[string]*rpctimeout.RPCTimeout{}
+	assert.Nil(t, c.callbackParser(param).Decode(YAML, "Echo:\n  rpc_timeout_ms: 1.5s\n  conn_timeout_ms: 50\n", &timeouts))
+	assert.Equal(t, &rpctimeout.RPCTimeout{RPCTimeoutMS: 1500, ConnTimeoutMS: 50}, timeouts["Echo"])
+
+	policies := map[string]*retry.Policy{}
+	assert.Nil(t, c.callbackParser(param).Decode(JSON, `{"*": {"enable": true, "type": 0, "failure_policy": {
+		"stop_policy": {"max_retry_times": 2, "max_duration_ms": "2s", "cb_policy": {"error_rate": "10%"}},
+		"backoff_policy": {"backoff_type": "fixed", "cfg_items": {"fix_ms": "500ms"}}}}}`, &policies))
+	fp := policies["*"].FailurePolicy
+	assert.Equal(t, uint32(2000), fp.StopPolicy.MaxDurationMS)
+	assert.Equal(t, 0.1, fp.StopPolicy.CBPolicy.ErrorRate)
+	assert.Equal(t, float64(500), fp.BackOffPolicy.CfgItems[retry.FixMSBackOffCfgKey])
+
+	cbs := map[string]*circuitbreak.CBConfig{}
+	assert.Nil(t, c.callbackParser(param).Decode(TOML, "[Echo]\nenable = true\nerr_rate = \"1.5%\"\nmin_sample = 100\n", &cbs))
+	assert.Equal(t, 0.015, cbs["Echo"].ErrRate)
+	assert.Nil(t, c.callbackParser(param).Decode(JSON, `{"Echo": {"err_rate": 0.015}}`, &cbs))
+	assert.Equal(t, 0.015, cbs["Echo"].ErrRate)
+
+	dc := &degradation.Config{}
+	assert.Nil(t, c.callbackParser(param).Decode(PROPERTIES, "enable=true\npercentage=30%\n", dc))
+	assert.Equal(t, &degradation.Config{Enable: true, Percentage: 30}, dc)
+	// the numbers are always percentages
+	assert.Nil(t, c.callbackParser(param).Decode(YAML, "percentage: \"45\"\nrules:\n- percentage: 1\n", dc))
+	assert.Equal(t, 45, dc.Percentage)
+	assert.Equal(t, 1, dc.Rules[0].Percentage)
+
+	var r struct {
+		MaxRetryPercentage float64 `json:"max_retry_percentage"`
+	}
+	assert.Nil(t, c.callbackParser(param).Decode(JSON, `{"max_retry_percentage": 0.5}`, &r))
+	assert.Equal(t, 0.5, r.MaxRetryPercentage)
+	assert.Nil(t, c.callbackParser(param).Decode(JSON, `{"max_retry_percentage": "1.5%"}`, &r))
+	assert.Equal(t, 1.5, r.MaxRetryPercentage)
+
+	// invalid values
+	assert.EqualError(t, c.callbackParser(param).Decode(JSON, `{"Echo": {"rpc_timeout_ms": "1000"}}`, &timeouts),
+		`Echo.rpc_timeout_ms: invalid duration "1000"`)
+	assert.EqualError(t, c.callbackParser(param).Decode(JSON, `{"Echo": {"rpc_timeout_ms": "1.5ms"}}`, &timeouts),
+		`Echo.rpc_timeout_ms: duration "1.5ms" is not a whole number of milliseconds`)
+	assert.EqualError(t, c.callbackParser(param).Decode(JSON, `{"percentage": "half"}`, dc),
+		`percentage: invalid percentage "half"`)
+	assert.EqualError(t, c.callbackParser(param).Decode(JSON, `{"percentage": "1.5%"}`, dc),
+		`percentage: percentage 1.5% is not a whole number`)
+	assert.EqualError(t, c.callbackParser(param).Decode(JSON, `{"percentage": 0.3}`, dc),
+		`percentage: percentage 0.3% is not a whole number`)
+
+	// the normalized values pass the strict mode
+	d := c.callbackParser(param)
+	Strict(d)
+	assert.Nil(t, d.Decode(JSON, `{"Echo": {"rpc_timeout_ms": "2s"}}`, &timeouts))
+	assert.Equal(t, 2000, timeouts["Echo"].RPCTimeoutMS)
+
+	// the rollout percent is not normalized, 0.5 is 0.5%
+	matched := 0
+	for i := 0; i < 10000; i++ {
+		c.instance = &Instance{IP: fmt.Sprintf("10.%d.%d.1", i/256, i%256)}
+		got := limit{}
+		assert.Nil(t, c.callbackParser(param).Decode(JSON, `{"rollout": {"percent": 0.5}, "config": {"qps": 1}}`, &got))
+		matched += got.QPS
+	}
+	assert.InDelta(t, 50, matched, 30)
+}
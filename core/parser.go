@@ -0,0 +1,121 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/BurntSushi/toml"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	NacosDefaultServerAddr   = "127.0.0.1"
+	NacosDefaultPort         = 8848
+	NacosDefaultConfigGroup  = "DEFAULT_GROUP"
+	NacosDefaultClientDataID = "{{.ClientServiceName}}.{{.ServerServiceName}}.{{.Category}}"
+	NacosDefaultServerDataID = "{{.ServerServiceName}}.{{.Category}}"
+	NacosDefaultHistorySize  = 10
+
+	NacosDefaultSignatureSuffix = ".sig"
+)
+
+const (
+	defaultContent = ""
+)
+
+// ConfigParamConfig use for render the dataId or group info by go template, ref: https://pkg.go.dev/text/template
+// The fixed key shows as below.
+type ConfigParamConfig struct {
+	Category          string
+	ClientServiceName string
+	ServerServiceName string
+}
+
+var _ ConfigParser = &parser{}
+
+// ConfigParser the parser for nacos config.
+type ConfigParser interface {
+	Decode(kind, data string, config interface{}) error
+}
+
+// ConfigParserFunc is an adapter to allow the use of ordinary functions as ConfigParser.
+type ConfigParserFunc func(kind, data string, config interface{}) error
+
+// Decode calls f(kind, data, config).
+func (f ConfigParserFunc) Decode(kind, data string, config interface{}) error {
+	return f(kind, data, config)
+}
+
+var (
+	parsersLock sync.RWMutex
+	parsers     = map[string]ConfigParser{
+		JSON:       ConfigParserFunc(decodeYAML),
+		YAML:       ConfigParserFunc(decodeYAML),
+		TOML:       ConfigParserFunc(decodeTOML),
+		PROPERTIES: ConfigParserFunc(decodeProperties),
+	}
+)
+
+// RegisterParser registers the parser of the config type for the default parser, the built-in
+// parsers of json, yaml, toml and properties can be replaced as well.
+func RegisterParser(kind string, parser ConfigParser) {
+	parsersLock.Lock()
+	defer parsersLock.Unlock()
+	parsers[kind] = parser
+}
+
+func lookupParser(kind string) (ConfigParser, bool) {
+	parsersLock.RLock()
+	defer parsersLock.RUnlock()
+	p, ok := parsers[kind]
+	return p, ok
+}
+
+type parser struct{}
+
+// Decode decodes the data to struct with the parser registered for the type.
+func (p *parser) Decode(kind, data string, config interface{}) error {
+	registered, ok := lookupParser(kind)
+	if !ok {
+		return fmt.Errorf("unsupported config data type %s", kind)
+	}
+	return registered.Decode(kind, data, config)
+}
+
+func decodeYAML(_, data string, config interface{}) error {
+	// since YAML is a superset of JSON, it can parse JSON using a YAML parser
+	return yaml.Unmarshal([]byte(data), config)
+}
+
+// decodeTOML decodes the TOML data to the generic tree and maps it onto the json tags of the config.
+func decodeTOML(_, data string, config interface{}) error {
+	tree := map[string]interface{}{}
+	if _, err := toml.Decode(data, &tree); err != nil {
+		return err
+	}
+	buf, err := json.Marshal(tree)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(buf, config)
+}
+
+// DefaultConfigParse default nacos config parser.
+func defaultConfigParse() ConfigParser {
+	return &parser{}
+}
@@ -0,0 +1,150 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/cloudwego/kitex/pkg/retry"
+	"github.com/cloudwego/kitex/pkg/rpctimeout"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeTOML(t *testing.T) {
+	p := defaultConfigParse()
+
+	rcs := map[string]*retry.Policy{}
+	assert.Nil(t, p.Decode(TOML, `
+["*"]
+enable = true
+type = 0
+
+["*".failure_policy.stop_policy]
+max_retry_times = 3
+max_duration_ms = 2000
+
+["*".failure_policy.stop_policy.cb_policy]
+error_rate = 0.1
+
+["*".failure_policy.backoff_policy]
+backoff_type = "fixed"
+cfg_items = { fix_ms = 50 }
+`, &rcs))
+	policy := rcs["*"]
+	assert.True(t, policy.Enable)
+	assert.Equal(t, retry.FailureType, policy.Type)
+	assert.Equal(t, 3, policy.FailurePolicy.StopPolicy.MaxRetryTimes)
+	assert.Equal(t, uint32(2000), policy.FailurePolicy.StopPolicy.MaxDurationMS)
+	assert.Equal(t, 0.1, policy.FailurePolicy.StopPolicy.CBPolicy.ErrorRate)
+	assert.Equal(t, retry.FixedBackOffType, policy.FailurePolicy.BackOffPolicy.BackOffType)
+	assert.Equal(t, float64(50), policy.FailurePolicy.BackOffPolicy.CfgItems[retry.FixMSBackOffCfgKey])
+
+	timeouts := map[string]*rpctimeout.RPCTimeout{}
+	assert.Nil(t, p.Decode(TOML, `
+[Echo]
+rpc_timeout_ms = 100
+conn_timeout_ms = 50
+`, &timeouts))
+	assert.Equal(t, &rpctimeout.RPCTimeout{RPCTimeoutMS: 100, ConnTimeoutMS: 50}, timeouts["Echo"])
+
+	assert.NotNil(t, p.Decode(TOML, `[Echo`, &timeouts))
+	assert.NotNil(t, p.Decode("xml", `<a/>`, &timeouts))
+}
+
+func TestDecodeProperties(t *testing.T) {
+	p := defaultConfigParse()
+
+	rcs := map[string]*retry.Policy{}
+	assert.Nil(t, p.Decode(PROPERTIES, `
+# retry policy for all methods
+*.enable=true
+*.type = 0
+*.failure_policy.stop_policy.max_retry_times: 3
+*.failure_policy.stop_policy.cb_policy.error_rate 0.1
+*.failure_policy.backoff_policy.backoff_type=fixed
+*.failure_policy.backoff_policy.cfg_items.fix_ms=50
+! multi-line value
+*.failure_policy.extra=first \
+    second
+`, &rcs))
+	policy := rcs["*"]
+	assert.True(t, policy.Enable)
+	assert.Equal(t, 3, policy.FailurePolicy.StopPolicy.MaxRetryTimes)
+	assert.Equal(t, 0.1, policy.FailurePolicy.StopPolicy.CBPolicy.ErrorRate)
+	assert.Equal(t, retry.FixedBackOffType, policy.FailurePolicy.BackOffPolicy.BackOffType)
+	assert.Equal(t, float64(50), policy.FailurePolicy.BackOffPolicy.CfgItems[retry.FixMSBackOffCfgKey])
+	assert.Equal(t, "first second", policy.FailurePolicy.Extra)
+
+	// escapes, unicode and lists
+	got := map[string]interface{}{}
+	assert.Nil(t, p.Decode(PROPERTIES, `
+a\=b\ c=x\:y\\z
+unicode=你好
+escaped=\u0074rue
+emoji=\uD83D\uDE00 \ud83d\ude00
+lone=\uD83Dx
+empty
+list[1]=second
+list[0]=first
+`, &got))
+	assert.Equal(t, map[string]interface{}{
+		"a=b c":   `x:y\z`,
+		"unicode": "你好",
+		"escaped": "true",
+		"emoji":   "😀 😀",
+		"lone":    "\uFFFDx",
+		"empty":   "",
+		"list":    []interface{}{"first", "second"},
+	}, got)
+
+	assert.NotNil(t, p.Decode(PROPERTIES, "a=1\na.b=2", &got))
+	assert.NotNil(t, p.Decode(PROPERTIES, `a=\u12`, &got))
+}
+
+func TestPropertiesRoundTrip(t *testing.T) {
+	p := defaultConfigParse()
+
+	rcs := map[string]*retry.Policy{
+		"Echo": {
+			Enable: true,
+			Type:   retry.FailureType,
+			FailurePolicy: &retry.FailurePolicy{
+				StopPolicy: retry.StopPolicy{MaxRetryTimes: 2, CBPolicy: retry.CBPolicy{ErrorRate: 0.2}},
+				BackOffPolicy: &retry.BackOffPolicy{
+					BackOffType: retry.RandomBackOffType,
+					CfgItems:    map[retry.BackOffCfgKey]float64{retry.MinMSBackOffCfgKey: 10, retry.MaxMSBackOffCfgKey: 20.5},
+				},
+				Extra: " line1\nline2 = 你好 #!\\ ",
+			},
+		},
+	}
+	data, err := MarshalProperties(rcs)
+	assert.Nil(t, err)
+	got := map[string]*retry.Policy{}
+	assert.Nil(t, p.Decode(PROPERTIES, data, &got))
+	assert.Equal(t, rcs, got)
+
+	values := map[string]interface{}{
+		"key with=:#! chars": "true",
+		"number":             "42",
+		"list":               []interface{}{"a", false, "-1.5"},
+		"nested":             map[string]interface{}{"tab": "\t\f\r", "control": "\x01"},
+	}
+	data, err = MarshalProperties(values)
+	assert.Nil(t, err)
+	decoded := map[string]interface{}{}
+	assert.Nil(t, p.Decode(PROPERTIES, data, &decoded))
+	assert.Equal(t, values, decoded)
+}
@@ -0,0 +1,345 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+var (
+	propertiesIndex   = regexp.MustCompile(`^(.*)\[(\d+)\]$`)
+	propertiesInteger = regexp.MustCompile(`^-?(0|[1-9][0-9]*)$`)
+	propertiesFloat   = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][-+]?[0-9]+)?$`)
+)
+
+type property struct {
+	key   string
+	value string
+	// literal the value is kept as a string, which is written with escapes.
+	literal bool
+}
+
+// decodeProperties decodes the Java style properties, the dotted keys like `*.stop_policy.max_retry_times=3`
+// are turned into the nested structure and mapped onto the json tags of the config. The list items are
+// written as `key[0]=value`. The values of true, false and numbers are typed like YAML, others and the
+// values written with escapes are strings.
+func decodeProperties(_, data string, config interface{}) error {
+	props, err := parseProperties(data)
+	if err != nil {
+		return err
+	}
+	var tree interface{} = map[string]interface{}{}
+	for _, p := range props {
+		var value interface{} = p.value
+		if !p.literal {
+			value = typedProperty(p.value)
+		}
+		if tree, err = setProperty(tree, strings.Split(p.key, "."), value); err != nil {
+			return fmt.Errorf("property %s: %w", p.key, err)
+		}
+	}
+	buf, err := json.Marshal(compactLists(tree))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(buf, config)
+}
+
+// parseProperties parses the lines in the format of java.util.Properties.
+func parseProperties(data string) ([]property, error) {
+	var props []property
+	lines := strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimLeft(lines[i], " \t\f")
+		if line == "" || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+		// the line ends with an odd number of backslashes continues on the next line
+		for continued(line) && i+1 < len(lines) {
+			i++
+			line = line[:len(line)-1] + strings.TrimLeft(lines[i], " \t\f")
+		}
+		if continued(line) {
+			line = line[:len(line)-1]
+		}
+		rawKey, rawValue := splitProperty(line)
+		key, err := unescapeProperty(rawKey)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		value, err := unescapeProperty(rawValue)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		props = append(props, property{key: key, value: value, literal: strings.Contains(rawValue, `\`)})
+	}
+	return props, nil
+}
+
+func continued(line string) bool {
+	n := 0
+	for i := len(line) - 1; i >= 0 && line[i] == '\\'; i-- {
+		n++
+	}
+	return n%2 == 1
+}
+
+// splitProperty splits the line at the first unescaped '=', ':' or whitespace.
+func splitProperty(line string) (string, string) {
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '=', ':':
+			return line[:i], strings.TrimLeft(line[i+1:], " \t\f")
+		case ' ', '\t', '\f':
+			rest := strings.TrimLeft(line[i:], " \t\f")
+			if rest != "" && (rest[0] == '=' || rest[0] == ':') {
+				rest = strings.TrimLeft(rest[1:], " \t\f")
+			}
+			return line[:i], rest
+		}
+	}
+	return line, ""
+}
+
+func unescapeProperty(s string) (string, error) {
+	if !strings.Contains(s, `\`) {
+		return s, nil
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 'f':
+			b.WriteByte('\f')
+		case 'u':
+			r, ok := hexRune(s, i+1)
+			if !ok {
+				return "", fmt.Errorf("malformed \\uxxxx escape in %q", s)
+			}
+			i += 4
+			// the characters out of the BMP are escaped as the utf-16 surrogate pairs, e.g. \uD83D\uDE00
+			if utf16.IsSurrogate(r) && strings.HasPrefix(s[i+1:], `\u`) {
+				if low, ok := hexRune(s, i+3); ok {
+					if pair := utf16.DecodeRune(r, low); pair != unicode.ReplacementChar {
+						r = pair
+						i += 6
+					}
+				}
+			}
+			b.WriteRune(r)
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String(), nil
+}
+
+// hexRune parses the 4 hex digits at i of the \uxxxx escape.
+func hexRune(s string, i int) (rune, bool) {
+	if i+4 > len(s) {
+		return 0, false
+	}
+	r, err := strconv.ParseUint(s[i:i+4], 16, 16)
+	if err != nil {
+		return 0, false
+	}
+	return rune(r), true
+}
+
+func typedProperty(value string) interface{} {
+	switch {
+	case value == "true":
+		return true
+	case value == "false":
+		return false
+	case propertiesInteger.MatchString(value), propertiesFloat.MatchString(value):
+		return json.Number(value)
+	}
+	return value
+}
+
+// listNode the list written by indexes, which is compacted into a slice in order of the indexes.
+type listNode map[int]interface{}
+
+func setProperty(node interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		if node != nil {
+			return nil, fmt.Errorf("conflicts with the nested keys")
+		}
+		return value, nil
+	}
+	name, index := path[0], -1
+	if m := propertiesIndex.FindStringSubmatch(name); m != nil {
+		name = m[1]
+		index, _ = strconv.Atoi(m[2])
+	}
+	obj, ok := node.(map[string]interface{})
+	if node != nil && !ok {
+		return nil, fmt.Errorf("conflicts with the value of the parent key")
+	}
+	if obj == nil {
+		obj = map[string]interface{}{}
+	}
+	if index < 0 {
+		child, err := setProperty(obj[name], path[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		obj[name] = child
+		return obj, nil
+	}
+	list, ok := obj[name].(listNode)
+	if obj[name] != nil && !ok {
+		return nil, fmt.Errorf("%s is not a list", name)
+	}
+	if list == nil {
+		list = listNode{}
+	}
+	child, err := setProperty(list[index], path[1:], value)
+	if err != nil {
+		return nil, err
+	}
+	list[index] = child
+	obj[name] = list
+	return obj, nil
+}
+
+func compactLists(node interface{}) interface{} {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			v[k] = compactLists(child)
+		}
+	case listNode:
+		indexes := make([]int, 0, len(v))
+		for i := range v {
+			indexes = append(indexes, i)
+		}
+		sort.Ints(indexes)
+		list := make([]interface{}, 0, len(v))
+		for _, i := range indexes {
+			list = append(list, compactLists(v[i]))
+		}
+		return list
+	}
+	return node
+}
+
+// MarshalProperties encodes the config into the properties format decoded by the default parser,
+// the keys are sorted and escaped. It's used by the tools publishing the properties configs.
+func MarshalProperties(config interface{}) (string, error) {
+	buf, err := json.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(buf))
+	decoder.UseNumber()
+	var tree interface{}
+	if err = decoder.Decode(&tree); err != nil {
+		return "", err
+	}
+	var props []property
+	flattenProperties("", tree, &props)
+	sort.Slice(props, func(i, j int) bool { return props[i].key < props[j].key })
+	var b strings.Builder
+	for _, p := range props {
+		b.WriteString(escapeProperty(p.key, true))
+		b.WriteByte('=')
+		value := p.value
+		if p.literal {
+			// escape the first rune to keep the value as a string, e.g. \u0074rue
+			r, size := utf8.DecodeRuneInString(value)
+			fmt.Fprintf(&b, `\u%04x`, r)
+			value = value[size:]
+		}
+		b.WriteString(escapeProperty(value, false))
+		b.WriteByte('\n')
+	}
+	return b.String(), nil
+}
+
+func flattenProperties(prefix string, node interface{}, props *[]property) {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			key := k
+			if prefix != "" {
+				key = prefix + "." + k
+			}
+			flattenProperties(key, child, props)
+		}
+	case []interface{}:
+		for i, child := range v {
+			flattenProperties(fmt.Sprintf("%s[%d]", prefix, i), child, props)
+		}
+	case string:
+		_, isString := typedProperty(v).(string)
+		*props = append(*props, property{key: prefix, value: v, literal: !isString})
+	case nil:
+	default:
+		*props = append(*props, property{key: prefix, value: fmt.Sprint(v)})
+	}
+}
+
+func escapeProperty(s string, isKey bool) string {
+	var b strings.Builder
+	for i, r := range s {
+		switch r {
+		case '\\':
+			b.WriteString(`\\`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '=', ':', '#', '!', ' ':
+			if isKey || i == 0 {
+				b.WriteByte('\\')
+			}
+			b.WriteRune(r)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(&b, `\u%04x`, r)
+				continue
+			}
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,200 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"net"
+	"strings"
+
+	"github.com/cloudwego/kitex/pkg/klog"
+)
+
+const (
+	rolloutKey  = "rollout"
+	configKey   = "config"
+	fallbackKey = "fallback"
+
+	// rolloutBuckets the granularity of the rollout percent, 0.01% per bucket.
+	rolloutBuckets = 10000
+)
+
+// Rollout describes which instances apply the config of a rollout envelope.
+// The percent is applied to the instances matching the selectors, nil percent means 100.
+type Rollout struct {
+	Percent   *float64          `json:"percent"`
+	Selectors *RolloutSelectors `json:"selectors"`
+}
+
+// RolloutSelectors all the non-empty selectors must match the instance.
+// ip supports both single address and CIDR.
+type RolloutSelectors struct {
+	IP     []string          `json:"ip"`
+	Pod    []string          `json:"pod"`
+	Labels map[string]string `json:"labels"`
+}
+
+// rolloutEnvelope the payload shape for gradual rollout:
+//
+//	{"rollout": {...}, "config": {...}, "fallback": {...}}
+type rolloutEnvelope struct {
+	Rollout  *Rollout    `json:"rollout"`
+	Config   interface{} `json:"config"`
+	Fallback interface{} `json:"fallback"`
+}
+
+// Match reports whether the instance should apply the rollout config.
+func (r *Rollout) Match(ins *Instance) bool {
+	if r.Selectors != nil && !r.Selectors.match(ins) {
+		return false
+	}
+	if r.Percent == nil {
+		return true
+	}
+	return rolloutBucket(ins) < int(*r.Percent*rolloutBuckets/100)
+}
+
+func (s *RolloutSelectors) match(ins *Instance) bool {
+	if len(s.IP) > 0 && !matchIP(s.IP, ins.IP) {
+		return false
+	}
+	if len(s.Pod) > 0 && !contains(s.Pod, ins.Pod) {
+		return false
+	}
+	for k, v := range s.Labels {
+		if ins.Labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *Rollout) validate() error {
+	if r.Percent != nil && (*r.Percent < 0 || *r.Percent > 100) {
+		return fmt.Errorf("rollout percent %v out of range [0, 100]", *r.Percent)
+	}
+	if r.Selectors == nil {
+		return nil
+	}
+	for _, ip := range r.Selectors.IP {
+		if strings.Contains(ip, "/") {
+			if _, _, err := net.ParseCIDR(ip); err != nil {
+				return fmt.Errorf("rollout ip selector %s: %w", ip, err)
+			}
+		}
+	}
+	return nil
+}
+
+// rolloutBucket hashes the instance identity into [0, rolloutBuckets), the same
+// instance always falls into the same bucket.
+func rolloutBucket(ins *Instance) int {
+	h := fnv.New32a()
+	h.Write([]byte(ins.key()))
+	return int(h.Sum32() % rolloutBuckets)
+}
+
+func matchIP(selectors []string, ip string) bool {
+	addr := net.ParseIP(ip)
+	for _, s := range selectors {
+		if !strings.Contains(s, "/") {
+			if s == ip {
+				return true
+			}
+			continue
+		}
+		_, cidr, err := net.ParseCIDR(s)
+		if err == nil && addr != nil && cidr.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// isRolloutEnvelope the rollout key is required and only the envelope keys are allowed,
+// so a method named `rollout` in a per-method config is not misjudged.
+func isRolloutEnvelope(tree map[string]interface{}) bool {
+	if _, ok := tree[rolloutKey]; !ok {
+		return false
+	}
+	for k := range tree {
+		if k != rolloutKey && k != configKey && k != fallbackKey {
+			return false
+		}
+	}
+	return true
+}
+
+var _ ConfigParser = &rolloutParser{}
+
+// rolloutParser unwraps the rollout envelope before decoding the config.
+// Payloads without the envelope are decoded by the underlying parser as is.
+type rolloutParser struct {
+	parser ConfigParser
+	// envelope decodes the envelope as is, the human-friendly values are normalized in the branch only,
+	// so the rollout percent keeps its precision.
+	envelope ConfigParser
+	instance *Instance
+	dataID   string
+}
+
+// Decode decodes the branch of the rollout envelope the instance selects.
+func (p *rolloutParser) Decode(kind, data string, config interface{}) error {
+	tree := map[string]interface{}{}
+	if err := p.envelope.Decode(kind, data, &tree); err != nil || !isRolloutEnvelope(tree) {
+		return p.parser.Decode(kind, data, config)
+	}
+
+	buf, err := json.Marshal(tree)
+	if err != nil {
+		return err
+	}
+	envelope := &rolloutEnvelope{}
+	if err = json.Unmarshal(buf, envelope); err != nil {
+		return fmt.Errorf("invalid rollout envelope: %w", err)
+	}
+	if envelope.Rollout == nil {
+		return fmt.Errorf("invalid rollout envelope: empty rollout")
+	}
+	if err = envelope.Rollout.validate(); err != nil {
+		return err
+	}
+
+	branch, name := envelope.Fallback, fallbackKey
+	if envelope.Rollout.Match(p.instance) {
+		branch, name = envelope.Config, configKey
+	}
+	klog.Debugf("[nacos] config %s rollout selects %s for instance %s", p.dataID, name, p.instance.key())
+	if branch == nil {
+		// the instance is not selected and no fallback, take it as an empty config.
+		branch = map[string]interface{}{}
+	}
+	buf, err = json.Marshal(branch)
+	if err != nil {
+		return err
+	}
+	return p.parser.Decode(JSON, string(buf), config)
+}
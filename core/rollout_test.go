@@ -0,0 +1,85 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type limit struct {
+	QPS int `json:"qps"`
+}
+
+func TestRolloutParser(t *testing.T) {
+	ins := &Instance{IP: "10.0.0.1", Pod: "pod-a", Labels: map[string]string{"zone": "a"}}
+	p := &rolloutParser{parser: defaultConfigParse(), envelope: defaultConfigParse(), instance: ins}
+
+	// no envelope
+	got := limit{}
+	assert.Nil(t, p.Decode(JSON, `{"qps": 1}`, &got))
+	assert.Equal(t, limit{QPS: 1}, got)
+
+	// selected by the selectors
+	got = limit{}
+	assert.Nil(t, p.Decode(JSON, `{
+		"rollout": {"selectors": {"ip": ["10.0.0.0/24"], "labels": {"zone": "a"}}},
+		"config": {"qps": 2},
+		"fallback": {"qps": 3}
+	}`, &got))
+	assert.Equal(t, limit{QPS: 2}, got)
+
+	// not selected by the selectors
+	got = limit{}
+	assert.Nil(t, p.Decode(YAML, `
+rollout:
+  selectors:
+    pod: [pod-b]
+config:
+  qps: 2
+fallback:
+  qps: 3
+`, &got))
+	assert.Equal(t, limit{QPS: 3}, got)
+
+	// not selected and no fallback
+	got = limit{QPS: 4}
+	assert.Nil(t, p.Decode(JSON, `{"rollout": {"percent": 0}, "config": {"qps": 2}}`, &got))
+	assert.Equal(t, limit{QPS: 4}, got)
+
+	// a method named rollout is not an envelope
+	methods := map[string]limit{}
+	assert.Nil(t, p.Decode(JSON, `{"rollout": {"qps": 5}, "echo": {"qps": 6}}`, &methods))
+	assert.Equal(t, map[string]limit{"rollout": {QPS: 5}, "echo": {QPS: 6}}, methods)
+
+	assert.NotNil(t, p.Decode(JSON, `{"rollout": {"percent": 101}, "config": {}}`, &got))
+}
+
+func TestRolloutPercent(t *testing.T) {
+	percent := 30.0
+	r := &Rollout{Percent: &percent}
+	matched := 0
+	for i := 0; i < 10000; i++ {
+		ins := &Instance{IP: fmt.Sprintf("10.%d.%d.1", i/256, i%256)}
+		if r.Match(ins) {
+			matched++
+		}
+		// deterministic for the same instance
+		assert.Equal(t, r.Match(ins), r.Match(&Instance{IP: ins.IP}))
+	}
+	assert.InDelta(t, 3000, matched, 300)
+}
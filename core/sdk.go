@@ -0,0 +1,45 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+// The config types, the same as the ones of the nacos sdk except TOML.
+const (
+	JSON       = "json"
+	YAML       = "yaml"
+	TOML       = "toml"
+	PROPERTIES = "properties"
+	TEXT       = "text"
+)
+
+// ConfigParam the parameters of a nacos config, the fields are the same as the ones of the nacos sdk.
+type ConfigParam struct {
+	DataId  string
+	Group   string
+	Content string
+	// DatumId is supported by the nacos sdk v1 only.
+	DatumId  string
+	Type     string
+	OnChange func(namespace, group, dataId, data string)
+	// Native the param of the nacos sdk it's converted from, which keeps the fields unknown to the core.
+	Native interface{}
+}
+
+// ConfigClient the subset of the nacos config client used by the core, the nacos packages of the v1 and v2
+// modules adapt the nacos sdk to it.
+type ConfigClient interface {
+	GetConfig(param ConfigParam) (string, error)
+	ListenConfig(param ConfigParam) error
+	CancelListenConfig(param ConfigParam) error
+}
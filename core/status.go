@@ -0,0 +1,93 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "time"
+
+// Status the apply status of a subscription, the counters can be exported as metrics.
+type Status struct {
+	// Version the latest applied version, 0 if nothing has been applied.
+	Version int64
+	// Pinned the version pinned by Rollback, 0 if not pinned.
+	Pinned int64
+	// Applied the number of contents applied by the callbacks.
+	Applied uint64
+	// Rejected the number of contents rejected by the callbacks, whose previous config is kept.
+	Rejected uint64
+	// LastError the reason of the last rejection.
+	LastError      error
+	LastRejectedAt time.Time
+	// LastDryRunDiff the difference between the effective config and the last dry-run content, which is not applied.
+	LastDryRunDiff []string
+	LastDryRunAt   time.Time
+}
+
+// Reject marks the content delivered with the parser as rejected, the callback keeps the previous config.
+// Decode errors are marked automatically, Reject is used for the contents which fail the validation.
+func Reject(parser ConfigParser, err error) {
+	if d, ok := parser.(*delivery); ok && err != nil {
+		d.rejected = err
+	}
+}
+
+// DryRun marks the content delivered with the parser as dry-run, it is not applied and the diff
+// against the effective config shows up in the Status of the subscription.
+func DryRun(parser ConfigParser, diff []string) {
+	if d, ok := parser.(*delivery); ok {
+		d.dryRun = true
+		d.diff = diff
+	}
+}
+
+func (h *history) reject(key configParam, err error) {
+	h.Lock()
+	defer h.Unlock()
+	vh := h.get(key)
+	vh.rejected++
+	vh.lastError = err
+	vh.lastRejectedAt = time.Now()
+}
+
+func (h *history) dryRun(key configParam, diff []string) {
+	h.Lock()
+	defer h.Unlock()
+	vh := h.get(key)
+	vh.lastDryRunDiff = diff
+	vh.lastDryRunAt = time.Now()
+}
+
+func (h *history) status(key configParam) Status {
+	h.Lock()
+	defer h.Unlock()
+	vh, ok := h.items[key]
+	if !ok {
+		return Status{}
+	}
+	s := Status{
+		Applied:        vh.applied,
+		Rejected:       vh.rejected,
+		LastError:      vh.lastError,
+		LastRejectedAt: vh.lastRejectedAt,
+		LastDryRunDiff: vh.lastDryRunDiff,
+		LastDryRunAt:   vh.lastDryRunAt,
+	}
+	if latest := vh.latest(); latest != nil {
+		s.Version = latest.Version
+	}
+	if vh.pinned != nil {
+		s.Pinned = vh.pinned.Version
+	}
+	return s
+}
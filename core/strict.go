@@ -0,0 +1,121 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"fmt"
+
+	yamlv3 "gopkg.in/yaml.v3"
+
+	"github.com/kitex-contrib/config-nacos/pkg/schema"
+)
+
+// Strict decodes the content delivered with the parser in strict mode, which rejects the unknown
+// and duplicate fields. It's used to enable the strict mode for some categories only.
+func Strict(parser ConfigParser) {
+	if d, ok := parser.(*delivery); ok {
+		d.strict = true
+	}
+}
+
+var _ ConfigParser = &strictParser{}
+
+// strictParser checks the unknown and duplicate fields before decoding if the delivery is strict.
+type strictParser struct {
+	parser ConfigParser
+	strict *bool
+}
+
+// Decode implements ConfigParser.
+func (p *strictParser) Decode(kind, data string, config interface{}) error {
+	if *p.strict {
+		if err := checkStrict(p.parser, kind, data, config); err != nil {
+			return err
+		}
+	}
+	return p.parser.Decode(kind, data, config)
+}
+
+// checkStrict reports the path of the first duplicate field, or the first field unknown to the config.
+func checkStrict(parser ConfigParser, kind, data string, config interface{}) error {
+	if err := checkDuplicates(kind, data); err != nil {
+		return err
+	}
+	var tree interface{}
+	if err := parser.Decode(kind, data, &tree); err != nil {
+		return err
+	}
+	return schema.Generate(config).Validate(tree)
+}
+
+// checkDuplicates checks the duplicate fields of json, yaml and properties, toml rejects them itself.
+func checkDuplicates(kind, data string) error {
+	switch kind {
+	case JSON, YAML:
+		var node yamlv3.Node
+		if err := yamlv3.Unmarshal([]byte(data), &node); err != nil {
+			return err
+		}
+		return duplicateFields("", &node)
+	case PROPERTIES:
+		props, err := parseProperties(data)
+		if err != nil {
+			return err
+		}
+		seen := map[string]bool{}
+		for _, p := range props {
+			if seen[p.key] {
+				return fmt.Errorf("%s: duplicate field", p.key)
+			}
+			seen[p.key] = true
+		}
+	}
+	return nil
+}
+
+func duplicateFields(path string, node *yamlv3.Node) error {
+	switch node.Kind {
+	case yamlv3.DocumentNode:
+		for _, child := range node.Content {
+			if err := duplicateFields(path, child); err != nil {
+				return err
+			}
+		}
+	case yamlv3.MappingNode:
+		seen := map[string]bool{}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			child := key
+			if path != "" {
+				child = path + "." + key
+			}
+			// the merge keys can be repeated
+			if seen[key] && key != "<<" {
+				return fmt.Errorf("%s: duplicate field", child)
+			}
+			seen[key] = true
+			if err := duplicateFields(child, node.Content[i+1]); err != nil {
+				return err
+			}
+		}
+	case yamlv3.SequenceNode:
+		for i, child := range node.Content {
+			if err := duplicateFields(fmt.Sprintf("%s[%d]", path, i), child); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
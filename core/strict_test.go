@@ -0,0 +1,56 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/cloudwego/kitex/pkg/retry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStrictDecoding(t *testing.T) {
+	c := &client{
+		parser:   defaultConfigParse(),
+		instance: &Instance{},
+	}
+	param := ConfigParam{DataId: "d1", Group: "g1"}
+	decode := func(strict bool, kind, data string) error {
+		d := c.callbackParser(param)
+		if strict {
+			Strict(d)
+		}
+		rcs := map[string]*retry.Policy{}
+		return d.Decode(kind, data, &rcs)
+	}
+
+	typo := `{"*": {"enable": true, "failure_policy": {"stop_policy": {"max_retry_time": 3}}}}`
+	assert.Nil(t, decode(false, JSON, typo))
+	assert.EqualError(t, decode(true, JSON, typo), "*.failure_policy.stop_policy.max_retry_time: unknown field")
+	assert.Nil(t, decode(true, JSON, `{"*": {"enable": true, "failure_policy": {"stop_policy": {"max_retry_times": 3}}}}`))
+
+	duplicate := `{"*": {"enable": true, "enable": false}}`
+	assert.Nil(t, decode(false, JSON, duplicate))
+	assert.EqualError(t, decode(true, JSON, duplicate), "*.enable: duplicate field")
+	assert.EqualError(t, decode(true, YAML, "m1:\n  type: 0\nm2: {}\nm1: {}\n"), "m1: duplicate field")
+	assert.EqualError(t, decode(true, PROPERTIES, "m1.enable=true\nm1.enable=false"), "m1.enable: duplicate field")
+	assert.EqualError(t, decode(true, PROPERTIES, "m1.failure_policy.retry_same_nodes=true"),
+		"m1.failure_policy.retry_same_nodes: unknown field")
+	assert.NotNil(t, decode(true, TOML, "[m1]\nenable = true\nenable = false"))
+
+	// strict for all the deliveries
+	c.strict = true
+	assert.NotNil(t, decode(false, JSON, typo))
+}
@@ -0,0 +1,336 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/cloudwego/kitex/pkg/klog"
+)
+
+// The supported signature algorithms.
+const (
+	SignatureEd25519    = "ed25519"
+	SignatureHMACSHA256 = "hmac-sha256"
+)
+
+// UnsignedPolicy decides how to handle the contents without signature when the Verifier is set.
+type UnsignedPolicy int
+
+const (
+	// UnsignedReject rejects the unsigned contents and keeps the previous config.
+	UnsignedReject UnsignedPolicy = iota
+	// UnsignedWarn applies the unsigned contents with a warning.
+	UnsignedWarn
+	// UnsignedAllow applies the unsigned contents silently.
+	UnsignedAllow
+)
+
+// ErrUnsigned is returned when the content is not signed and the UnsignedPolicy is UnsignedReject.
+var ErrUnsigned = errors.New("config is not signed")
+
+// Signature the signature of a config payload.
+type Signature struct {
+	KeyID     string `json:"key_id"`
+	Algorithm string `json:"alg"`
+	// Value the base64 encoded signature.
+	Value string `json:"sig"`
+}
+
+// signedEnvelope the embedded signature, the payload is kept as a string so the signed bytes are exact.
+type signedEnvelope struct {
+	Payload   string     `json:"payload"`
+	Signature *Signature `json:"signature"`
+}
+
+// Verifier verifies the signature of the payload. Implement it to integrate an external trust store.
+type Verifier interface {
+	Verify(payload []byte, sig *Signature) error
+}
+
+// VerifierFunc is an adapter to allow the use of ordinary functions as Verifier.
+type VerifierFunc func(payload []byte, sig *Signature) error
+
+// Verify calls f(payload, sig).
+func (f VerifierFunc) Verify(payload []byte, sig *Signature) error {
+	return f(payload, sig)
+}
+
+// TrustedKey a key trusted by the KeySetVerifier.
+type TrustedKey struct {
+	ID        string
+	Algorithm string
+	// Key the public key for ed25519 or the secret for hmac-sha256.
+	Key []byte
+}
+
+// KeySetVerifier verifies the Ed25519 and HMAC-SHA256 signatures with a set of trusted keys.
+// The key set can be replaced at runtime to rotate the keys.
+type KeySetVerifier struct {
+	lock sync.RWMutex
+	keys map[string]TrustedKey
+}
+
+// NewKeySetVerifier creates a KeySetVerifier trusting the keys.
+func NewKeySetVerifier(keys ...TrustedKey) *KeySetVerifier {
+	v := &KeySetVerifier{}
+	v.SetKeys(keys...)
+	return v
+}
+
+// SetKeys replaces the trusted keys, the signatures of the removed keys are rejected afterwards.
+func (v *KeySetVerifier) SetKeys(keys ...TrustedKey) {
+	m := make(map[string]TrustedKey, len(keys))
+	for _, k := range keys {
+		m[k.ID] = k
+	}
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	v.keys = m
+}
+
+// Verify implements Verifier.
+func (v *KeySetVerifier) Verify(payload []byte, sig *Signature) error {
+	v.lock.RLock()
+	key, ok := v.keys[sig.KeyID]
+	v.lock.RUnlock()
+	if !ok {
+		return fmt.Errorf("untrusted key %s", sig.KeyID)
+	}
+	// the algorithm is bound to the key, never trust the one declared in the signature alone
+	if key.Algorithm != sig.Algorithm {
+		return fmt.Errorf("algorithm %s mismatches the key %s", sig.Algorithm, sig.KeyID)
+	}
+	value, err := base64.StdEncoding.DecodeString(sig.Value)
+	if err != nil {
+		return fmt.Errorf("decode signature failed: %w", err)
+	}
+	switch key.Algorithm {
+	case SignatureEd25519:
+		if len(key.Key) != ed25519.PublicKeySize {
+			return fmt.Errorf("invalid ed25519 public key %s", key.ID)
+		}
+		if !ed25519.Verify(key.Key, payload, value) {
+			return fmt.Errorf("invalid signature of key %s", key.ID)
+		}
+	case SignatureHMACSHA256:
+		if !hmac.Equal(hmacSHA256(key.Key, payload), value) {
+			return fmt.Errorf("invalid signature of key %s", key.ID)
+		}
+	default:
+		return fmt.Errorf("unsupported signature algorithm %s", key.Algorithm)
+	}
+	return nil
+}
+
+func hmacSHA256(secret, payload []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// SignEd25519 signs the payload with the ed25519 private key. It's used by the tools publishing the signed configs.
+func SignEd25519(keyID string, key ed25519.PrivateKey, payload []byte) *Signature {
+	return &Signature{
+		KeyID:     keyID,
+		Algorithm: SignatureEd25519,
+		Value:     base64.StdEncoding.EncodeToString(ed25519.Sign(key, payload)),
+	}
+}
+
+// SignHMACSHA256 signs the payload with the hmac secret. It's used by the tools publishing the signed configs.
+func SignHMACSHA256(keyID string, secret, payload []byte) *Signature {
+	return &Signature{
+		KeyID:     keyID,
+		Algorithm: SignatureHMACSHA256,
+		Value:     base64.StdEncoding.EncodeToString(hmacSHA256(secret, payload)),
+	}
+}
+
+// SignedContent returns the bytes to sign for the content of the dataId in the group, which binds the
+// signature to the dataId, so a signed payload can't be replayed onto another dataId.
+func SignedContent(group, dataID, content string) []byte {
+	return []byte(group + "/" + dataID + "/" + content)
+}
+
+// EmbedSignature wraps the payload and its signature into the embedded form.
+func EmbedSignature(payload string, sig *Signature) (string, error) {
+	buf, err := json.Marshal(signedEnvelope{Payload: payload, Signature: sig})
+	if err != nil {
+		return "", err
+	}
+	return string(buf), nil
+}
+
+// embeddedSignature returns false if the data is not in the embedded form.
+func embeddedSignature(data string) (*signedEnvelope, bool) {
+	if !strings.HasPrefix(strings.TrimSpace(data), "{") {
+		return nil, false
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(data), &fields); err != nil || len(fields) != 2 {
+		return nil, false
+	}
+	if _, ok := fields["payload"]; !ok {
+		return nil, false
+	}
+	if _, ok := fields["signature"]; !ok {
+		return nil, false
+	}
+	env := &signedEnvelope{}
+	if err := json.Unmarshal([]byte(data), env); err != nil || env.Signature == nil {
+		return nil, false
+	}
+	return env, true
+}
+
+// verify checks the signature of the data and returns the signed payload. The embedded signature is
+// preferred, otherwise the detached one is read from the dataId with the signature suffix in the same group.
+// The empty content is verified as well, so blanking the dataId can't wipe the policies without a signature.
+// The pinned version is not verified again, as its detached signature may have been replaced since.
+func (c *client) verify(param ConfigParam, data string) (string, error) {
+	if c.verifier == nil {
+		return data, nil
+	}
+	if payload, ok := c.history.verified(configParamKey(param), data); ok {
+		return payload, nil
+	}
+	payload, sig := data, (*Signature)(nil)
+	if env, ok := embeddedSignature(data); ok {
+		payload, sig = env.Payload, env.Signature
+	} else {
+		detached, err := c.detachedSignature(param)
+		if err != nil {
+			return "", fmt.Errorf("get detached signature failed: %w", err)
+		}
+		if strings.TrimSpace(detached) != "" {
+			sig = &Signature{}
+			if err = json.Unmarshal([]byte(detached), sig); err != nil {
+				return "", fmt.Errorf("decode detached signature failed: %w", err)
+			}
+		}
+	}
+	if sig == nil {
+		switch c.unsignedPolicy {
+		case UnsignedAllow:
+			return data, nil
+		case UnsignedWarn:
+			klog.Warnf("[nacos] config %s in group %s is not signed, apply it anyway", param.DataId, param.Group)
+			return data, nil
+		default:
+			return "", ErrUnsigned
+		}
+	}
+	if err := c.verifier.Verify(SignedContent(param.Group, param.DataId, payload), sig); err != nil {
+		return "", err
+	}
+	return payload, nil
+}
+
+// signatures tracks the detached signatures listened for the subscriptions, the zero value is ready to use.
+type signatures struct {
+	sync.Mutex
+	// id the unique id to listen the signature dataIds
+	id int64
+	// contents the latest detached signatures delivered by nacos
+	contents map[configParam]string
+	// listened the subscriptions whose detached signatures are listened
+	listened map[configParam]bool
+}
+
+func (s *signatures) init() {
+	if s.id == 0 {
+		s.id = GetUniqueID()
+		s.contents = map[configParam]string{}
+		s.listened = map[configParam]bool{}
+	}
+}
+
+func (c *client) signatureKey(key configParam) configParam {
+	return configParam{DataID: key.DataID + c.signatureSuffix, Group: key.Group}
+}
+
+// detachedSignature returns the latest detached signature of the dataId.
+func (c *client) detachedSignature(param ConfigParam) (string, error) {
+	key := c.signatureKey(configParamKey(param))
+	c.signatures.Lock()
+	content, ok := c.signatures.contents[key]
+	c.signatures.Unlock()
+	if ok {
+		return content, nil
+	}
+	return c.ncli.GetConfig(ConfigParam{DataId: key.DataID, Group: key.Group})
+}
+
+// listenSignature listens the detached signature of the subscription, so the payload rejected for the
+// missing or stale signature is verified again once the new signature is published.
+func (c *client) listenSignature(key configParam) {
+	if c.verifier == nil {
+		return
+	}
+	c.signatures.Lock()
+	c.signatures.init()
+	if c.signatures.listened[key] {
+		c.signatures.Unlock()
+		return
+	}
+	c.signatures.listened[key] = true
+	id := c.signatures.id
+	c.signatures.Unlock()
+
+	sigKey := c.signatureKey(key)
+	c.listenConfig(ConfigParam{
+		DataId: sigKey.DataID,
+		Group:  sigKey.Group,
+		OnChange: func(namespace, group, dataId, data string) {
+			c.signatureChanged(key, sigKey, data)
+		},
+	}, id)
+}
+
+// unlistenSignature cancels listening the detached signature of the subscription.
+func (c *client) unlistenSignature(key configParam) {
+	c.signatures.Lock()
+	if !c.signatures.listened[key] {
+		c.signatures.Unlock()
+		return
+	}
+	delete(c.signatures.listened, key)
+	sigKey := c.signatureKey(key)
+	delete(c.signatures.contents, sigKey)
+	id := c.signatures.id
+	c.signatures.Unlock()
+	if err := c.DeregisterConfig(ConfigParam{DataId: sigKey.DataID, Group: sigKey.Group}, id); err != nil {
+		klog.Warnf("[nacos] cancel listening the signature config %v failed %v", sigKey, err)
+	}
+}
+
+// signatureChanged verifies the pending payload of the subscription again with the new signature.
+func (c *client) signatureChanged(key, sigKey configParam, data string) {
+	c.signatures.Lock()
+	c.signatures.init()
+	c.signatures.contents[sigKey] = data
+	c.signatures.Unlock()
+	klog.Infof("[nacos] the signature of config %v changed, deliver it again", key)
+	c.dispatch(key, "", c.history.current(key))
+}
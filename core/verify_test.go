@@ -0,0 +1,161 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerify(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	assert.Nil(t, err)
+	secret := []byte("secret")
+	verifier := NewKeySetVerifier(
+		TrustedKey{ID: "ed", Algorithm: SignatureEd25519, Key: pub},
+		TrustedKey{ID: "mac", Algorithm: SignatureHMACSHA256, Key: secret},
+	)
+	fake := &fakeNacos{
+		handlers: map[configParam]callbackHandler{},
+		configs:  map[configParam]string{},
+	}
+	c := &client{
+		ncli:            fake,
+		parser:          defaultConfigParse(),
+		instance:        &Instance{},
+		handlers:        map[configParam]map[int64]callbackHandler{},
+		verifier:        verifier,
+		signatureSuffix: NacosDefaultSignatureSuffix,
+	}
+	param := ConfigParam{DataId: "d1", Group: "g1", Type: JSON}
+	key := configParamKey(param)
+
+	var applied limit
+	c.RegisterConfigCallback(param, func(data string, parser ConfigParser) {
+		got := limit{}
+		if err := parser.Decode(param.Type, data, &got); err != nil {
+			return
+		}
+		applied = got
+	}, GetUniqueID())
+
+	// embedded ed25519 signature
+	payload := `{"qps": 1}`
+	signed, err := EmbedSignature(payload, SignEd25519("ed", priv, SignedContent("g1", "d1", payload)))
+	assert.Nil(t, err)
+	fake.change(key, signed)
+	assert.Equal(t, limit{QPS: 1}, applied)
+
+	// tampered payload
+	tampered, err := EmbedSignature(`{"qps": 100}`, SignEd25519("ed", priv, SignedContent("g1", "d1", payload)))
+	assert.Nil(t, err)
+	fake.change(key, tampered)
+	assert.Equal(t, limit{QPS: 1}, applied)
+	// the unsigned empty content at startup is rejected as well
+	assert.Equal(t, uint64(2), c.Status(param).Rejected)
+
+	// detached hmac signature
+	payload = `{"qps": 2}`
+	sig, err := json.Marshal(SignHMACSHA256("mac", secret, SignedContent("g1", "d1", payload)))
+	assert.Nil(t, err)
+	fake.configs[configParam{DataID: "d1.sig", Group: "g1"}] = string(sig)
+	fake.change(key, payload)
+	assert.Equal(t, limit{QPS: 2}, applied)
+
+	// the algorithm must match the trusted key
+	mismatched := SignHMACSHA256("ed", pub, SignedContent("g1", "d1", `{"qps": 3}`))
+	signed, err = EmbedSignature(`{"qps": 3}`, mismatched)
+	assert.Nil(t, err)
+	fake.change(key, signed)
+	assert.Equal(t, limit{QPS: 2}, applied)
+
+	// unsigned content
+	delete(fake.configs, configParam{DataID: "d1.sig", Group: "g1"})
+	fake.change(key, `{"qps": 4}`)
+	assert.Equal(t, limit{QPS: 2}, applied)
+	assert.Equal(t, ErrUnsigned, c.Status(param).LastError)
+	c.unsignedPolicy = UnsignedWarn
+	fake.change(key, `{"qps": 5}`)
+	assert.Equal(t, limit{QPS: 5}, applied)
+
+	// rotate the keys
+	verifier.SetKeys(TrustedKey{ID: "mac2", Algorithm: SignatureHMACSHA256, Key: []byte("secret2")})
+	signed, err = EmbedSignature(`{"qps": 6}`, SignEd25519("ed", priv, SignedContent("g1", "d1", `{"qps": 6}`)))
+	assert.Nil(t, err)
+	fake.change(key, signed)
+	assert.Equal(t, limit{QPS: 5}, applied)
+	signed, err = EmbedSignature(`{"qps": 7}`, SignHMACSHA256("mac2", []byte("secret2"), SignedContent("g1", "d1", `{"qps": 7}`)))
+	assert.Nil(t, err)
+	fake.change(key, signed)
+	assert.Equal(t, limit{QPS: 7}, applied)
+}
+
+func TestVerifyDetachedSignatureLater(t *testing.T) {
+	secret := []byte("secret")
+	fake := &fakeNacos{
+		handlers: map[configParam]callbackHandler{},
+		configs:  map[configParam]string{},
+	}
+	c := &client{
+		ncli:            fake,
+		parser:          defaultConfigParse(),
+		instance:        &Instance{},
+		handlers:        map[configParam]map[int64]callbackHandler{},
+		verifier:        NewKeySetVerifier(TrustedKey{ID: "mac", Algorithm: SignatureHMACSHA256, Key: secret}),
+		signatureSuffix: NacosDefaultSignatureSuffix,
+	}
+	param := ConfigParam{DataId: "d1", Group: "g1", Type: JSON}
+	key := configParamKey(param)
+	sigKey := configParam{DataID: "d1.sig", Group: "g1"}
+
+	var applied limit
+	uniqueID := GetUniqueID()
+	c.RegisterConfigCallback(param, func(data string, parser ConfigParser) {
+		got := limit{}
+		if err := parser.Decode(param.Type, data, &got); err != nil {
+			return
+		}
+		applied = got
+	}, uniqueID)
+
+	// the payload published before its detached signature is verified again once the signature arrives
+	payload := `{"qps": 1}`
+	fake.change(key, payload)
+	assert.Equal(t, limit{}, applied)
+	sig, err := json.Marshal(SignHMACSHA256("mac", secret, SignedContent("g1", "d1", payload)))
+	assert.Nil(t, err)
+	fake.change(sigKey, string(sig))
+	assert.Equal(t, limit{QPS: 1}, applied)
+
+	// the payload signed for another dataId can't be replayed
+	signed, err := EmbedSignature(`{"qps": 2}`, SignHMACSHA256("mac", secret, SignedContent("g1", "d2", `{"qps": 2}`)))
+	assert.Nil(t, err)
+	fake.change(key, signed)
+	assert.Equal(t, limit{QPS: 1}, applied)
+
+	// the empty content is not applied without a signature
+	fake.change(sigKey, "")
+	fake.change(key, "")
+	assert.Equal(t, limit{QPS: 1}, applied)
+	assert.Equal(t, ErrUnsigned, c.Status(param).LastError)
+
+	// the signature is not listened after the subscription is deregistered
+	assert.Nil(t, c.DeregisterConfig(param, uniqueID))
+	_, ok := fake.handlers[sigKey]
+	assert.False(t, ok)
+}
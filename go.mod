@@ -3,6 +3,7 @@ module github.com/kitex-contrib/config-nacos
 go 1.21
 
 require (
+	github.com/BurntSushi/toml v1.3.2
 	github.com/bytedance/gopkg v0.1.1
 	github.com/cloudwego/kitex v0.11.3
 	github.com/cloudwego/kitex-examples v0.3.3
@@ -11,6 +12,7 @@ require (
 	github.com/pkg/errors v0.9.1
 	github.com/stretchr/testify v1.10.0
 	go.uber.org/atomic v1.11.0
+	gopkg.in/yaml.v3 v3.0.1
 	sigs.k8s.io/yaml v1.3.0
 )
 
@@ -61,7 +63,6 @@ require (
 	gopkg.in/ini.v1 v1.42.0 // indirect
 	gopkg.in/natefinch/lumberjack.v2 v2.0.0 // indirect
 	gopkg.in/yaml.v2 v2.4.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
 
 replace github.com/apache/thrift => github.com/apache/thrift v0.13.0
@@ -0,0 +1,200 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/cloudwego/kitex/client"
+	"github.com/cloudwego/kitex/pkg/circuitbreak"
+	"github.com/cloudwego/kitex/pkg/klog"
+	"github.com/cloudwego/kitex/pkg/rpcinfo"
+
+	"github.com/kitex-contrib/config-nacos/core"
+	"github.com/kitex-contrib/config-nacos/internal/utils"
+)
+
+// WithCircuitBreaker sets the circuit breaker policy from nacos configuration center.
+func WithCircuitBreaker(dest, src string, nacosClient core.Client, opts utils.Options) []client.Option {
+	param, err := nacosClient.ClientConfigParam(&core.ConfigParamConfig{
+		Category:          circuitBreakerConfigName,
+		ServerServiceName: dest,
+		ClientServiceName: src,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	instanceParam, err := nacosClient.ClientConfigParam(&core.ConfigParamConfig{
+		Category:          instanceCircuitBreakerConfigName,
+		ServerServiceName: dest,
+		ClientServiceName: src,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	uniqueID := core.GetUniqueID()
+
+	cbSuite := initCircuitBreaker(param, dest, src, nacosClient, uniqueID, opts)
+	initInstanceCircuitBreaker(instanceParam, dest, cbSuite, nacosClient, uniqueID, opts)
+
+	return []client.Option{
+		client.WithCircuitBreaker(cbSuite),
+		client.WithCloseCallbacks(func() error {
+			err := nacosClient.DeregisterConfig(param, uniqueID)
+			if err != nil {
+				return err
+			}
+			err = nacosClient.DeregisterConfig(instanceParam, uniqueID)
+			if err != nil {
+				return err
+			}
+			// cancel the configuration listener when client is closed.
+			return cbSuite.Close()
+		}),
+	}
+}
+
+func initInstanceCircuitBreaker(param core.ConfigParam, dest string, cb *circuitbreak.CBSuite,
+	nacosClient core.Client, uniqueID int64, opts utils.Options,
+) {
+	effective := circuitbreak.GetDefaultCBConfig()
+	// mu serializes the callbacks, the startup load and the listener may deliver concurrently.
+	var mu sync.Mutex
+
+	onChangeCallback := func(data string, parser core.ConfigParser) {
+		mu.Lock()
+		defer mu.Unlock()
+		// the fields absent from the config keep the kitex defaults.
+		config := circuitbreak.GetDefaultCBConfig()
+		opts.Strict(instanceCircuitBreakerConfigName, parser)
+		err := parser.Decode(param.Type, data, &config)
+		if err != nil {
+			klog.Warnf("[nacos] %s client nacos instance circuit breaker: unmarshal data %s failed: %s, skip...", dest, data, err)
+			return
+		}
+		if err = opts.ValidateSchema(instanceCircuitBreakerConfigName, param.Type, data, parser); err != nil {
+			klog.Warnf("[nacos] %s client nacos instance circuit breaker: data %s mismatches the schema: %s, skip...", dest, data, err)
+			core.Reject(parser, err)
+			return
+		}
+		if err = validate(instanceCircuitBreakerConfigName, config, &opts); err != nil {
+			klog.Warnf("[nacos] %s client nacos instance circuit breaker: invalid data %s: %s, skip...", dest, data, err)
+			core.Reject(parser, err)
+			return
+		}
+		if opts.DryRun("client", instanceCircuitBreakerConfigName, dest, parser, effective, config) {
+			return
+		}
+
+		cb.UpdateInstanceCBConfig(config)
+		effective = config
+	}
+
+	nacosClient.RegisterConfigCallback(param, onChangeCallback, uniqueID)
+}
+
+// keep consistent when initialising the circuit breaker suit and updating
+// the circuit breaker policy.
+func genServiceCBKeyWithRPCInfo(ri rpcinfo.RPCInfo) string {
+	if ri == nil {
+		return ""
+	}
+	return genServiceCBKey(ri.To().ServiceName(), ri.To().Method())
+}
+
+func genServiceCBKey(toService, method string) string {
+	sum := len(toService) + len(method) + 2
+	var buf strings.Builder
+	buf.Grow(sum)
+	buf.WriteString(toService)
+	buf.WriteByte('/')
+	buf.WriteString(method)
+	return buf.String()
+}
+
+func initCircuitBreaker(param core.ConfigParam, dest, src string,
+	nacosClient core.Client, uniqueID int64, opts utils.Options,
+) *circuitbreak.CBSuite {
+	var expander *methodExpander[circuitbreak.CBConfig]
+	cb := circuitbreak.NewCBSuite(func(ri rpcinfo.RPCInfo) string {
+		if ri != nil {
+			expander.observe(ri.To().Method())
+		}
+		return genServiceCBKeyWithRPCInfo(ri)
+	})
+	// the circuit breaker suite has no default for the methods of the service, so the wildcard "*"
+	// is applied on every called method and the explicit entries override it.
+	expander = newMethodExpander(true, func(configs map[string]circuitbreak.CBConfig, removed []string) {
+		def, ok := configs[utils.WildcardMethod]
+		if !ok {
+			def = circuitbreak.GetDefaultCBConfig()
+		}
+		for method, config := range configs {
+			if method != utils.WildcardMethod {
+				cb.UpdateServiceCBConfig(genServiceCBKey(dest, method), config)
+			}
+		}
+		for _, method := range removed {
+			// For deleted method configs, fall back to the wildcard or the default policy
+			if method != utils.WildcardMethod {
+				cb.UpdateServiceCBConfig(genServiceCBKey(dest, method), def)
+			}
+		}
+	})
+	effective := map[string]circuitbreak.CBConfig{}
+	// mu serializes the callbacks, the startup load and the listener may deliver concurrently.
+	var mu sync.Mutex
+
+	onChangeCallback := func(data string, parser core.ConfigParser) {
+		mu.Lock()
+		defer mu.Unlock()
+		configs := map[string]circuitbreak.CBConfig{}
+		opts.Strict(circuitBreakerConfigName, parser)
+		err := parser.Decode(param.Type, data, &configs)
+		if err != nil {
+			klog.Warnf("[nacos] %s client nacos rpc circuit breaker: unmarshal data %s failed: %s, skip...", dest, data, err)
+			return
+		}
+		if err = opts.ValidateSchema(circuitBreakerConfigName, param.Type, data, parser); err != nil {
+			klog.Warnf("[nacos] %s client nacos rpc circuit breaker: data %s mismatches the schema: %s, skip...", dest, data, err)
+			core.Reject(parser, err)
+			return
+		}
+		if err = validate(circuitBreakerConfigName, configs, &opts); err != nil {
+			klog.Warnf("[nacos] %s client nacos rpc circuit breaker: invalid data %s: %s, skip...", dest, data, err)
+			core.Reject(parser, err)
+			return
+		}
+		matcher, err := compileMethodKeys(configs)
+		if err != nil {
+			klog.Warnf("[nacos] %s client nacos rpc circuit breaker: invalid data %s: %s, skip...", dest, data, err)
+			core.Reject(parser, err)
+			return
+		}
+		if opts.DryRun("client", circuitBreakerConfigName, dest, parser, effective, configs) {
+			return
+		}
+
+		expander.update(configs, matcher)
+		effective = configs
+	}
+
+	nacosClient.RegisterConfigCallback(param, onChangeCallback, uniqueID)
+
+	return cb
+}
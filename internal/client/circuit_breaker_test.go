@@ -0,0 +1,98 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cloudwego/kitex/pkg/circuitbreak"
+	"github.com/cloudwego/kitex/pkg/rpcinfo"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kitex-contrib/config-nacos/core"
+	"github.com/kitex-contrib/config-nacos/internal/utils"
+)
+
+func serviceCBConfig(cb *circuitbreak.CBSuite, key string) (interface{}, bool) {
+	configs := cb.Dump().(map[string]interface{})["cb_config"].(map[string]interface{})["service"]
+	config, ok := configs.(map[string]interface{})[key]
+	return config, ok
+}
+
+func TestInstanceCircuitBreaker(t *testing.T) {
+	fake, cli := newFakeClient(t)
+	param, err := cli.ClientConfigParam(&core.ConfigParamConfig{
+		Category:          instanceCircuitBreakerConfigName,
+		ServerServiceName: "svc",
+		ClientServiceName: "cli",
+	})
+	assert.Nil(t, err)
+	cb := circuitbreak.NewCBSuite(genServiceCBKeyWithRPCInfo)
+	defer cb.Close()
+	initInstanceCircuitBreaker(param, "svc", cb, cli, 1, utils.Options{})
+
+	instanceConfig := func() interface{} {
+		return cb.Dump().(map[string]interface{})["cb_config"].(map[string]interface{})["instance"]
+	}
+	fake.change(param.DataId, `{"err_rate": 0.2, "min_sample": 50}`)
+	assert.Equal(t, circuitbreak.CBConfig{Enable: true, ErrRate: 0.2, MinSample: 50}, instanceConfig())
+
+	// the invalid config is rejected
+	fake.change(param.DataId, `{"err_rate": 2}`)
+	assert.Equal(t, circuitbreak.CBConfig{Enable: true, ErrRate: 0.2, MinSample: 50}, instanceConfig())
+
+	fake.change(param.DataId, `{"enable": false}`)
+	assert.False(t, instanceConfig().(circuitbreak.CBConfig).Enable)
+}
+
+func TestCircuitBreakerWildcard(t *testing.T) {
+	fake, cli := newFakeClient(t)
+	param, err := cli.ClientConfigParam(&core.ConfigParamConfig{
+		Category:          circuitBreakerConfigName,
+		ServerServiceName: "svc",
+		ClientServiceName: "cli",
+	})
+	assert.Nil(t, err)
+	fake.change(param.DataId, `{"*": {"enable": true, "err_rate": 0.3, "min_sample": 10}, "Echo": {"enable": false}}`)
+	cb := initCircuitBreaker(param, "svc", "cli", cli, 1, utils.Options{})
+	defer cb.Close()
+
+	wildcard := circuitbreak.CBConfig{Enable: true, ErrRate: 0.3, MinSample: 10}
+	call := func(method string) {
+		ri := rpcinfo.NewRPCInfo(nil, rpcinfo.NewEndpointInfo("svc", method, nil, nil), rpcinfo.NewInvocation("svc", method), nil, nil)
+		ctx := rpcinfo.NewCtxWithRPCInfo(context.Background(), ri)
+		key, _ := cb.ServiceControl().GetKey(ctx, nil)
+		assert.Equal(t, "svc/"+method, key)
+	}
+	call("Ping")
+	call("Echo")
+	config, _ := serviceCBConfig(cb, "svc/Ping")
+	assert.Equal(t, wildcard, config)
+	config, _ = serviceCBConfig(cb, "svc/Echo")
+	assert.Equal(t, circuitbreak.CBConfig{}, config)
+	_, ok := serviceCBConfig(cb, "svc/*")
+	assert.False(t, ok)
+
+	// the deleted method falls back to the wildcard
+	fake.change(param.DataId, `{"*": {"enable": true, "err_rate": 0.3, "min_sample": 10}}`)
+	config, _ = serviceCBConfig(cb, "svc/Echo")
+	assert.Equal(t, wildcard, config)
+
+	// and to the default policy without the wildcard
+	fake.change(param.DataId, `{}`)
+	config, _ = serviceCBConfig(cb, "svc/Ping")
+	assert.Equal(t, circuitbreak.GetDefaultCBConfig(), config)
+}
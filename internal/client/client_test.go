@@ -0,0 +1,70 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kitex-contrib/config-nacos/core"
+)
+
+type fakeNacos struct {
+	sync.RWMutex
+	handlers map[string]func(namespace, group, dataId, data string)
+	configs  map[string]string
+}
+
+func newFakeClient(t *testing.T) (*fakeNacos, core.Client) {
+	fake := &fakeNacos{
+		handlers: map[string]func(namespace, group, dataId, data string){},
+		configs:  map[string]string{},
+	}
+	cli, err := core.NewClient(fake, core.Options{})
+	assert.Nil(t, err)
+	return fake, cli
+}
+
+func (fn *fakeNacos) GetConfig(param core.ConfigParam) (string, error) {
+	fn.RLock()
+	defer fn.RUnlock()
+	return fn.configs[param.DataId], nil
+}
+
+func (fn *fakeNacos) ListenConfig(param core.ConfigParam) error {
+	fn.Lock()
+	defer fn.Unlock()
+	fn.handlers[param.DataId] = param.OnChange
+	return nil
+}
+
+func (fn *fakeNacos) CancelListenConfig(param core.ConfigParam) error {
+	fn.Lock()
+	defer fn.Unlock()
+	delete(fn.handlers, param.DataId)
+	return nil
+}
+
+func (fn *fakeNacos) change(dataID, data string) {
+	fn.Lock()
+	fn.configs[dataID] = data
+	handler, ok := fn.handlers[dataID]
+	fn.Unlock()
+	if ok {
+		handler("", core.NacosDefaultConfigGroup, dataID, data)
+	}
+}
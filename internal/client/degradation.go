@@ -15,18 +15,19 @@
 package client
 
 import (
+	"sync"
+
 	"github.com/cloudwego/kitex/client"
 	"github.com/cloudwego/kitex/pkg/klog"
 	"github.com/kitex-contrib/config-nacos/pkg/degradation"
-	"github.com/nacos-group/nacos-sdk-go/vo"
 
-	"github.com/kitex-contrib/config-nacos/nacos"
-	"github.com/kitex-contrib/config-nacos/utils"
+	"github.com/kitex-contrib/config-nacos/core"
+	"github.com/kitex-contrib/config-nacos/internal/utils"
 )
 
 // WithDegradation sets the degradation policy from nacos configuration center.
-func WithDegradation(dest, src string, nacosClient nacos.Client, opts utils.Options) []client.Option {
-	param, err := nacosClient.ClientConfigParam(&nacos.ConfigParamConfig{
+func WithDegradation(dest, src string, nacosClient core.Client, opts utils.Options) []client.Option {
+	param, err := nacosClient.ClientConfigParam(&core.ConfigParamConfig{
 		Category:          degradationName,
 		ServerServiceName: dest,
 		ClientServiceName: src,
@@ -35,13 +36,9 @@ func WithDegradation(dest, src string, nacosClient nacos.Client, opts utils.Opti
 		panic(err)
 	}
 
-	for _, f := range opts.NacosCustomFunctions {
-		f(&param)
-	}
-
-	uniqueID := nacos.GetUniqueID()
+	uniqueID := core.GetUniqueID()
 
-	degradationContainer := initDegradation(param, dest, src, nacosClient, uniqueID)
+	degradationContainer := initDegradation(param, dest, src, nacosClient, uniqueID, opts)
 
 	return []client.Option{
 		client.WithACLRules(degradationContainer.GetACLRule()),
@@ -56,20 +53,41 @@ func WithDegradation(dest, src string, nacosClient nacos.Client, opts utils.Opti
 	}
 }
 
-func initDegradation(param vo.ConfigParam, dest, src string,
-	nacosClient nacos.Client, uniqueID int64,
+func initDegradation(param core.ConfigParam, dest, src string,
+	nacosClient core.Client, uniqueID int64, opts utils.Options,
 ) *degradation.Container {
 	degradationContainer := degradation.NewDegradationContainer()
+	effective := degradation.Configs{}
+	// mu serializes the callbacks, the startup load and the listener may deliver concurrently.
+	var mu sync.Mutex
 
-	onChangeCallback := func(data string, parser nacos.ConfigParser) {
-		config := &degradation.Config{}
-		err := parser.Decode(param.Type, data, config)
+	onChangeCallback := func(data string, parser core.ConfigParser) {
+		mu.Lock()
+		defer mu.Unlock()
+		// the key is method name or wildcard "*".
+		config := degradation.Configs{}
+		opts.Strict(degradationName, parser)
+		err := parser.Decode(param.Type, data, &config)
 		if err != nil {
 			klog.Warnf("[nacos] %s client nacos rpc degradation: unmarshal data %s failed: %s, skip...", dest, data, err)
 			return
 		}
+		if err = opts.ValidateSchema(degradationName, param.Type, data, parser); err != nil {
+			klog.Warnf("[nacos] %s client nacos rpc degradation: data %s mismatches the schema: %s, skip...", dest, data, err)
+			core.Reject(parser, err)
+			return
+		}
+		if err = validate(degradationName, config, &opts); err != nil {
+			klog.Warnf("[nacos] %s client nacos rpc degradation: invalid data %s: %s, skip...", dest, data, err)
+			core.Reject(parser, err)
+			return
+		}
+		if opts.DryRun("client", degradationName, dest, parser, effective, config) {
+			return
+		}
 		// update degradation config
-		degradationContainer.NotifyPolicyChange(config)
+		degradationContainer.NotifyPoliciesChange(config)
+		effective = config
 	}
 
 	nacosClient.RegisterConfigCallback(param, onChangeCallback, uniqueID)
@@ -0,0 +1,88 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cloudwego/kitex/pkg/rpcinfo"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kitex-contrib/config-nacos/core"
+	"github.com/kitex-contrib/config-nacos/internal/utils"
+)
+
+func TestDegradation(t *testing.T) {
+	fake, cli := newFakeClient(t)
+	param, err := cli.ClientConfigParam(&core.ConfigParamConfig{
+		Category:          degradationName,
+		ServerServiceName: "svc",
+		ClientServiceName: "cli",
+	})
+	assert.Nil(t, err)
+	opts := utils.Options{}
+	utils.WithStrictDecoding().Apply(&opts)
+	utils.WithSchemaValidation().Apply(&opts)
+	container := initDegradation(param, "svc", "cli", cli, 1, opts)
+	reject := container.GetACLRule()
+	rejected := func(method string) bool {
+		ri := rpcinfo.NewRPCInfo(nil, rpcinfo.NewEndpointInfo("svc", method, nil, nil), rpcinfo.NewInvocation("svc", method), nil, nil)
+		return reject(rpcinfo.NewCtxWithRPCInfo(context.Background(), ri), nil) != nil
+	}
+
+	// the legacy single config applies to all the methods
+	fake.change(param.DataId, `{"enable": true, "percentage": "100%"}`)
+	assert.True(t, rejected("Recommend"))
+	assert.True(t, rejected("Checkout"))
+
+	fake.change(param.DataId, `{"*": {"enable": false}, "Recommend": {"enable": true, "percentage": 100}}`)
+	assert.True(t, rejected("Recommend"))
+	assert.False(t, rejected("Checkout"))
+
+	// the invalid config is rejected
+	fake.change(param.DataId, `{"Recommend": {"enable": true, "percentage": 101}}`)
+	assert.True(t, rejected("Recommend"))
+	fake.change(param.DataId, `{"Recommend": {"enable": true, "percentage": 100, "unknown": 1}}`)
+	assert.True(t, rejected("Recommend"))
+
+	// the schedule ended
+	fake.change(param.DataId, `{"enable": true, "percentage": 100, "schedule": {
+		"end": "2024-11-12T00:00:00+08:00", "daily": [{"start": "20:00", "end": "22:00"}],
+		"time_zone": "Asia/Shanghai", "ramp_up_ms": "10m", "ramp_down_ms": "10m"}}`)
+	assert.False(t, rejected("Recommend"))
+}
+
+func TestDegradationDryRun(t *testing.T) {
+	fake, cli := newFakeClient(t)
+	param, err := cli.ClientConfigParam(&core.ConfigParamConfig{
+		Category:          degradationName,
+		ServerServiceName: "svc",
+		ClientServiceName: "cli",
+	})
+	assert.Nil(t, err)
+	opts := utils.Options{}
+	utils.WithDryRun().Apply(&opts)
+	// the config loaded at startup is diffed against the code defaults
+	fake.change(param.DataId, `{"enable": true, "percentage": 100}`)
+	container := initDegradation(param, "svc", "cli", cli, 1, opts)
+	assert.Nil(t, container.GetACLRule()(context.Background(), nil))
+	assert.Equal(t, []string{`+ *: {"enable":true,"percentage":100}`}, cli.Status(param).LastDryRunDiff)
+
+	// so are the later changes
+	fake.change(param.DataId, `{"enable": true, "percentage": 50}`)
+	assert.Nil(t, container.GetACLRule()(context.Background(), nil))
+	assert.Equal(t, []string{`+ *: {"enable":true,"percentage":50}`}, cli.Status(param).LastDryRunDiff)
+}
@@ -0,0 +1,99 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"sync"
+
+	"github.com/kitex-contrib/config-nacos/internal/utils"
+)
+
+// methodExpander expands the glob and regular expression keys of a per-method config against the
+// methods actually called, so that the kitex containers only ever see method names and "*".
+// The wildcard "*" is expanded as well for the containers without a built-in default.
+type methodExpander[T any] struct {
+	wildcard bool
+	mu       sync.Mutex
+	configs  map[string]T
+	matcher  *utils.MethodMatcher
+	called   sync.Map
+	applied  utils.Set
+	// apply receives the expanded configs and the methods that no longer have one.
+	apply func(configs map[string]T, removed []string)
+}
+
+func newMethodExpander[T any](wildcard bool, apply func(configs map[string]T, removed []string)) *methodExpander[T] {
+	return &methodExpander[T]{wildcard: wildcard, applied: utils.Set{}, apply: apply}
+}
+
+// compileMethodKeys compiles the method keys of the configs before they are updated.
+func compileMethodKeys[T any](configs map[string]T) (*utils.MethodMatcher, error) {
+	keys := make([]string, 0, len(configs))
+	for key := range configs {
+		keys = append(keys, key)
+	}
+	return utils.NewMethodMatcher(keys)
+}
+
+// update replaces the configs and applies them to the methods called so far.
+func (e *methodExpander[T]) update(configs map[string]T, matcher *utils.MethodMatcher) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.configs, e.matcher = configs, matcher
+	e.expand()
+}
+
+// observe records the method on its first call and applies the config matched by a pattern.
+func (e *methodExpander[T]) observe(method string) {
+	if _, ok := e.called.Load(method); ok {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if _, loaded := e.called.LoadOrStore(method, true); loaded {
+		return
+	}
+	if key, ok := e.matcher.Match(method); ok && e.expands(key) {
+		e.expand()
+	}
+}
+
+// expands reports whether the methods matched by the key are applied one by one.
+func (e *methodExpander[T]) expands(key string) bool {
+	return utils.IsMethodPattern(key) || e.wildcard && key == utils.WildcardMethod
+}
+
+func (e *methodExpander[T]) expand() {
+	expanded := make(map[string]T, len(e.configs))
+	for key, config := range e.configs {
+		if !utils.IsMethodPattern(key) {
+			expanded[key] = config
+		}
+	}
+	e.called.Range(func(k, _ interface{}) bool {
+		method := k.(string)
+		if key, ok := e.matcher.Match(method); ok && e.expands(key) {
+			expanded[method] = e.configs[key]
+		}
+		return true
+	})
+	set := utils.Set{}
+	for method := range expanded {
+		set[method] = true
+	}
+	removed := e.applied.Diff(set)
+	e.applied = set
+	e.apply(expanded, removed)
+}
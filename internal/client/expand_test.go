@@ -0,0 +1,75 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/kitex/pkg/rpcinfo"
+	"github.com/cloudwego/kitex/pkg/rpctimeout"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMethodExpander(t *testing.T) {
+	var applied map[string]int
+	var removed []string
+	e := newMethodExpander(false, func(configs map[string]int, r []string) {
+		applied, removed = configs, r
+		sort.Strings(removed)
+	})
+
+	update := func(configs map[string]int) {
+		matcher, err := compileMethodKeys(configs)
+		assert.Nil(t, err)
+		e.update(configs, matcher)
+	}
+	update(map[string]int{"*": 1, "Get*": 2, "re:^List.+$": 3, "GetUser": 4})
+	assert.Equal(t, map[string]int{"*": 1, "GetUser": 4}, applied)
+
+	e.observe("GetOrder")
+	e.observe("ListOrders")
+	e.observe("Echo")
+	assert.Equal(t, map[string]int{"*": 1, "GetUser": 4, "GetOrder": 2, "ListOrders": 3}, applied)
+
+	update(map[string]int{"Get*": 5})
+	assert.Equal(t, map[string]int{"GetOrder": 5}, applied)
+	assert.Equal(t, []string{"*", "GetUser", "ListOrders"}, removed)
+
+	_, err := compileMethodKeys(map[string]int{"re:(": 1})
+	assert.NotNil(t, err)
+}
+
+func TestExpandedTimeoutProvider(t *testing.T) {
+	container := rpctimeout.NewContainer()
+	e := newMethodExpander(false, func(configs map[string]*rpctimeout.RPCTimeout, _ []string) {
+		container.NotifyPolicyChange(configs)
+	})
+	configs := map[string]*rpctimeout.RPCTimeout{
+		"*":    {RPCTimeoutMS: 100},
+		"Get*": {RPCTimeoutMS: 200},
+	}
+	matcher, err := compileMethodKeys(configs)
+	assert.Nil(t, err)
+	e.update(configs, matcher)
+
+	p := &expandedTimeoutProvider{container: container, expander: e}
+	ri := func(method string) rpcinfo.RPCInfo {
+		return rpcinfo.NewRPCInfo(nil, nil, rpcinfo.NewInvocation("svc", method), nil, nil)
+	}
+	assert.Equal(t, 200*time.Millisecond, p.Timeouts(ri("GetUser")).RPCTimeout())
+	assert.Equal(t, 100*time.Millisecond, p.Timeouts(ri("Echo")).RPCTimeout())
+}
@@ -0,0 +1,262 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/bytedance/gopkg/cloud/circuitbreaker"
+	"github.com/cloudwego/kitex/client"
+	"github.com/cloudwego/kitex/pkg/endpoint"
+	"github.com/cloudwego/kitex/pkg/kerrors"
+	"github.com/cloudwego/kitex/pkg/klog"
+	"github.com/cloudwego/kitex/pkg/retry"
+	"github.com/cloudwego/kitex/pkg/rpcinfo"
+
+	"github.com/kitex-contrib/config-nacos/core"
+	"github.com/kitex-contrib/config-nacos/internal/utils"
+	retrypolicy "github.com/kitex-contrib/config-nacos/pkg/retry"
+)
+
+// WithRetryPolicy sets the retry policy from nacos configuration center.
+func WithRetryPolicy(dest, src string, nacosClient core.Client, opts utils.Options) []client.Option {
+	param, err := nacosClient.ClientConfigParam(&core.ConfigParamConfig{
+		Category:          retryConfigName,
+		ServerServiceName: dest,
+		ClientServiceName: src,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	uniqueID := core.GetUniqueID()
+
+	rc, limits := initRetryContainer(param, dest, nacosClient, uniqueID, opts)
+	return []client.Option{
+		client.WithRetryContainer(rc),
+		// record the results of every call for the retry circuit breaker.
+		client.WithMiddleware(limits.middleware),
+		client.WithCloseCallbacks(func() error {
+			// cancel the configuration listener when client is closed.
+			err := nacosClient.DeregisterConfig(param, uniqueID)
+			if err != nil {
+				return err
+			}
+			limits.close()
+			return rc.Close()
+		}),
+	}
+}
+
+// the key of the disabled retryer, which the methods are routed to when their retries are stopped.
+const disabledRetryKey = "$disabled"
+
+// retryLimits applies the container limits which the kitex retry container can't update dynamically:
+// the cluster-wide switch and the retry circuit breaker.
+type retryLimits struct {
+	disabled atomic.Bool
+	breaker  atomic.Pointer[retrypolicy.CircuitBreaker]
+	panel    circuitbreaker.Panel
+}
+
+func newRetryLimits() *retryLimits {
+	// the panel never trips, only the metrics are used.
+	panel, _ := circuitbreaker.NewPanel(nil, circuitbreaker.Options{})
+	return &retryLimits{panel: panel}
+}
+
+func (l *retryLimits) update(c *retrypolicy.Container) {
+	if c == nil {
+		c = &retrypolicy.Container{}
+	}
+	l.disabled.Store(c.Disable)
+	l.breaker.Store(c.CircuitBreaker)
+}
+
+// stopped reports whether the retries of the method are stopped.
+func (l *retryLimits) stopped(method string) bool {
+	if l.disabled.Load() {
+		return true
+	}
+	cb := l.breaker.Load()
+	if cb == nil {
+		return false
+	}
+	m := l.panel.GetMetricer(method)
+	return m.Samples() >= cb.MinSample && m.ErrorRate() >= cb.ErrRate
+}
+
+func (l *retryLimits) middleware(next endpoint.Endpoint) endpoint.Endpoint {
+	return func(ctx context.Context, req, resp interface{}) error {
+		err := next(ctx, req, resp)
+		if l.breaker.Load() == nil {
+			return err
+		}
+		ri := rpcinfo.GetRPCInfo(ctx)
+		if ri == nil {
+			return err
+		}
+		method := ri.To().Method()
+		switch {
+		case err == nil:
+			l.panel.Succeed(method)
+		case kerrors.IsTimeoutError(err):
+			l.panel.Timeout(method)
+		default:
+			l.panel.Fail(method)
+		}
+		return err
+	}
+}
+
+func (l *retryLimits) close() {
+	l.panel.Close()
+}
+
+func initRetryContainer(param core.ConfigParam, dest string,
+	nacosClient core.Client, uniqueID int64, opts utils.Options,
+) (*retry.Container, *retryLimits) {
+	var expander *methodExpander[*retry.Policy]
+	limits := newRetryLimits()
+	retryContainer := retry.NewRetryContainer(
+		retry.WithContainerEnablePercentageLimit(),
+		retry.WithCustomizeKeyFunc(func(ctx context.Context, ri rpcinfo.RPCInfo) string {
+			method := ri.To().Method()
+			expander.observe(method)
+			if limits.stopped(method) {
+				return disabledRetryKey
+			}
+			return method
+		}),
+	)
+	retryContainer.NotifyPolicyChange(disabledRetryKey, retry.Policy{
+		Enable:        false,
+		Type:          retry.FailureType,
+		FailurePolicy: retry.NewFailurePolicy(),
+	})
+	expander = newMethodExpander(false, func(rcs map[string]*retry.Policy, removed []string) {
+		for method, policy := range rcs {
+			retryContainer.NotifyPolicyChange(method, *policy)
+		}
+		for _, method := range removed {
+			retryContainer.DeletePolicy(method)
+		}
+	})
+
+	effective := &retrypolicy.Config{}
+	// mu serializes the callbacks, the startup load and the listener may deliver concurrently.
+	var mu sync.Mutex
+
+	onChangeCallback := func(data string, parser core.ConfigParser) {
+		mu.Lock()
+		defer mu.Unlock()
+		// the key is method name, glob, regular expression or wildcard "*", besides the container limits.
+		rcs := &retrypolicy.Config{}
+		opts.Strict(retryConfigName, parser)
+		err := parser.Decode(param.Type, data, rcs)
+		if err != nil {
+			klog.Warnf("[nacos] %s client nacos retry: unmarshal data %s failed: %s, skip...", dest, data, err)
+			return
+		}
+
+		if err = opts.ValidateSchema(retryConfigName, param.Type, data, parser); err != nil {
+			klog.Warnf("[nacos] %s client nacos retry: data %s mismatches the schema: %s, skip...", dest, data, err)
+			core.Reject(parser, err)
+			return
+		}
+		if err = validate(retryConfigName, rcs, &opts); err != nil {
+			klog.Warnf("[nacos] %s client nacos retry: invalid data %s: %s, skip...", dest, data, err)
+			core.Reject(parser, err)
+			return
+		}
+		matcher, err := compileMethodKeys(rcs.Policies)
+		if err != nil {
+			klog.Warnf("[nacos] %s client nacos retry: invalid data %s: %s, skip...", dest, data, err)
+			core.Reject(parser, err)
+			return
+		}
+		policies, err := kitexPolicies(rcs)
+		if err != nil {
+			klog.Warnf("[nacos] %s client nacos retry: invalid data %s: %s, skip...", dest, data, err)
+			core.Reject(parser, err)
+			return
+		}
+		if opts.DryRun("client", retryConfigName, dest, parser, effective, rcs) {
+			return
+		}
+
+		limits.update(rcs.Container)
+		expander.update(policies, matcher)
+		effective = rcs
+	}
+
+	nacosClient.RegisterConfigCallback(param, onChangeCallback, uniqueID)
+
+	return retryContainer, limits
+}
+
+// kitexPolicies builds the kitex policies, the result retry conditions are set on the failure and mixed
+// policies and the retry percentages are capped by the container limit.
+func kitexPolicies(rcs *retrypolicy.Config) (map[string]*retry.Policy, error) {
+	var maxRate float64
+	if rcs.Container != nil {
+		maxRate = rcs.Container.MaxRetryPercentage / 100
+	}
+	policies := make(map[string]*retry.Policy, len(rcs.Policies))
+	for method, rc := range rcs.Policies {
+		policy := rc.Policy
+		var rr *retry.ShouldResultRetry
+		if rc.ResultRetry != nil {
+			var err error
+			if rr, err = rc.ResultRetry.ShouldResultRetry(); err != nil {
+				return nil, fmt.Errorf("result_retry of method %s: %w", method, err)
+			}
+		}
+		if policy.FailurePolicy != nil {
+			fp := *policy.FailurePolicy
+			capRetryRate(&fp.StopPolicy, maxRate)
+			if rr != nil {
+				fp.ShouldResultRetry = rr
+			}
+			policy.FailurePolicy = &fp
+		}
+		if policy.MixedPolicy != nil {
+			mp := *policy.MixedPolicy
+			capRetryRate(&mp.StopPolicy, maxRate)
+			if rr != nil {
+				mp.ShouldResultRetry = rr
+			}
+			policy.MixedPolicy = &mp
+		}
+		if policy.BackupPolicy != nil {
+			bp := *policy.BackupPolicy
+			capRetryRate(&bp.StopPolicy, maxRate)
+			policy.BackupPolicy = &bp
+		}
+		policies[method] = &policy
+	}
+	return policies, nil
+}
+
+// capRetryRate caps the retry circuit breaker rate, which is the percentage of the retry requests
+// with the percentage limit of the container.
+func capRetryRate(sp *retry.StopPolicy, maxRate float64) {
+	if maxRate > 0 && (sp.CBPolicy.ErrorRate <= 0 || sp.CBPolicy.ErrorRate > maxRate) {
+		sp.CBPolicy.ErrorRate = maxRate
+	}
+}
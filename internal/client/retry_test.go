@@ -0,0 +1,149 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cloudwego/kitex/pkg/kerrors"
+	"github.com/cloudwego/kitex/pkg/retry"
+	"github.com/cloudwego/kitex/pkg/rpcinfo"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kitex-contrib/config-nacos/core"
+	"github.com/kitex-contrib/config-nacos/internal/utils"
+)
+
+func TestRetryResultRetry(t *testing.T) {
+	fake, cli := newFakeClient(t)
+	param, err := cli.ClientConfigParam(&core.ConfigParamConfig{
+		Category:          retryConfigName,
+		ServerServiceName: "svc",
+		ClientServiceName: "cli",
+	})
+	assert.Nil(t, err)
+	rc, limits := initRetryContainer(param, "svc", cli, 1, utils.Options{})
+	defer rc.Close()
+	defer limits.close()
+
+	resultRetry := func() map[string]bool {
+		dump := rc.Dump().(map[string]interface{})["Echo"].(map[string]interface{})
+		rr := dump["specified_result_retry"].(map[string]bool)
+		return map[string]bool{"error_retry": rr["error_retry"], "resp_retry": rr["resp_retry"]}
+	}
+	fake.change(param.DataId, `{"Echo": {"enable": true, "type": 0, "failure_policy": {"stop_policy": {"max_retry_times": 2}},
+		"result_retry": {"biz_status_codes": [503]}}}`)
+	assert.Equal(t, map[string]bool{"error_retry": true, "resp_retry": true}, resultRetry())
+
+	fake.change(param.DataId, `{"Echo": {"enable": true, "type": 0, "failure_policy": {"stop_policy": {"max_retry_times": 2}},
+		"result_retry": {"resp_fields": [{"field": "BaseResp.StatusCode", "value": 503}]}}}`)
+	assert.Equal(t, map[string]bool{"error_retry": false, "resp_retry": true}, resultRetry())
+
+	fake.change(param.DataId, `{"Echo": {"enable": true, "type": 0, "failure_policy": {"stop_policy": {"max_retry_times": 2}}}}`)
+	assert.Equal(t, map[string]bool{"error_retry": false, "resp_retry": false}, resultRetry())
+}
+
+func TestRetryContainerLimits(t *testing.T) {
+	fake, cli := newFakeClient(t)
+	param, err := cli.ClientConfigParam(&core.ConfigParamConfig{
+		Category:          retryConfigName,
+		ServerServiceName: "svc",
+		ClientServiceName: "cli",
+	})
+	assert.Nil(t, err)
+	rc, limits := initRetryContainer(param, "svc", cli, 1, utils.Options{StrictCategories: map[string]bool{utils.StrictAll: true}})
+	defer rc.Close()
+	defer limits.close()
+
+	errorRate := func(method string) float64 {
+		dump := rc.Dump().(map[string]interface{})[method].(map[string]interface{})
+		return dump["failure_retry"].(*retry.FailurePolicy).StopPolicy.CBPolicy.ErrorRate
+	}
+	fake.change(param.DataId, `{
+		"$container": {"max_retry_percentage": "5%", "circuit_breaker": {"err_rate": 0.5, "min_sample": 4}},
+		"Echo": {"enable": true, "type": 0, "failure_policy": {"stop_policy": {"max_retry_times": 2, "cb_policy": {"error_rate": 0.2}}}},
+		"Ping": {"enable": true, "type": 0, "failure_policy": {"stop_policy": {"max_retry_times": 2, "cb_policy": {"error_rate": 0.01}}}}
+	}`)
+	assert.Equal(t, 0.05, errorRate("Echo"))
+	assert.Equal(t, 0.01, errorRate("Ping"))
+
+	// the retries of the method stop once its error rate reaches the threshold
+	ri := rpcinfo.NewRPCInfo(nil, rpcinfo.NewEndpointInfo("svc", "Echo", nil, nil), rpcinfo.NewInvocation("svc", "Echo"), nil, nil)
+	ctx := rpcinfo.NewCtxWithRPCInfo(context.Background(), ri)
+	call := limits.middleware(func(ctx context.Context, req, resp interface{}) error {
+		return kerrors.ErrRemoteOrNetwork
+	})
+	for i := 0; i < 3; i++ {
+		assert.NotNil(t, call(ctx, nil, nil))
+	}
+	assert.False(t, limits.stopped("Echo"))
+	assert.NotNil(t, call(ctx, nil, nil))
+	assert.True(t, limits.stopped("Echo"))
+	assert.False(t, limits.stopped("Ping"))
+
+	// the unknown fields of the container limits are rejected in strict mode
+	fake.change(param.DataId, `{"$container": {"disabled": true}}`)
+	assert.False(t, limits.stopped("Ping"))
+
+	fake.change(param.DataId, `{"$container": {"disable": true}}`)
+	assert.True(t, limits.stopped("Ping"))
+	_, ok := rc.Dump().(map[string]interface{})["Ping"]
+	assert.False(t, ok)
+}
+
+func TestRetryPolicyTransitions(t *testing.T) {
+	fake, cli := newFakeClient(t)
+	param, err := cli.ClientConfigParam(&core.ConfigParamConfig{
+		Category:          retryConfigName,
+		ServerServiceName: "svc",
+		ClientServiceName: "cli",
+	})
+	assert.Nil(t, err)
+	rc, limits := initRetryContainer(param, "svc", cli, 1, utils.Options{})
+	defer rc.Close()
+	defer limits.close()
+
+	retryer := func() map[string]interface{} {
+		return rc.Dump().(map[string]interface{})["Echo"].(map[string]interface{})
+	}
+	failure := `{"Echo": {"enable": true, "type": 0, "failure_policy": {"stop_policy": {"max_retry_times": 2}}}}`
+	mixed := `{"Echo": {"enable": true, "type": 2, "mixed_policy": {"retry_delay_ms": 50, "stop_policy": {"max_retry_times": 3}},
+		"result_retry": {"biz_status_codes": [503]}}}`
+	backup := `{"Echo": {"enable": true, "type": 1, "backup_policy": {"retry_delay_ms": 50, "stop_policy": {"max_retry_times": 1}}}}`
+
+	fake.change(param.DataId, failure)
+	assert.Contains(t, retryer(), "failure_retry")
+
+	fake.change(param.DataId, mixed)
+	dump := retryer()
+	mp := dump["mixed_retry"].(*retry.MixedPolicy)
+	assert.Equal(t, uint32(50), mp.RetryDelayMS)
+	assert.Equal(t, 3, mp.StopPolicy.MaxRetryTimes)
+	assert.True(t, dump["specified_result_retry"].(map[string]bool)["resp_retry"])
+
+	fake.change(param.DataId, backup)
+	assert.Contains(t, retryer(), "backup_request")
+
+	// the mixed policy with both the failure and backup policies is rejected
+	fake.change(param.DataId, `{"Echo": {"enable": true, "type": 2, "mixed_policy": {"retry_delay_ms": 50},
+		"backup_policy": {"retry_delay_ms": 50}}}`)
+	assert.Contains(t, retryer(), "backup_request")
+
+	fake.change(param.DataId, mixed)
+	assert.Contains(t, retryer(), "mixed_retry")
+	fake.change(param.DataId, failure)
+	assert.Contains(t, retryer(), "failure_retry")
+}
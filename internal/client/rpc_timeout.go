@@ -0,0 +1,109 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"sync"
+
+	"github.com/cloudwego/kitex/client"
+	"github.com/cloudwego/kitex/pkg/klog"
+	"github.com/cloudwego/kitex/pkg/rpcinfo"
+	"github.com/cloudwego/kitex/pkg/rpctimeout"
+	"github.com/kitex-contrib/config-nacos/core"
+	"github.com/kitex-contrib/config-nacos/internal/utils"
+)
+
+// WithRPCTimeout sets the RPC timeout policy from nacos configuration center.
+func WithRPCTimeout(dest, src string, nacosClient core.Client, opts utils.Options) []client.Option {
+	param, err := nacosClient.ClientConfigParam(&core.ConfigParamConfig{
+		Category:          rpcTimeoutConfigName,
+		ServerServiceName: dest,
+		ClientServiceName: src,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	uniqueID := core.GetUniqueID()
+
+	return []client.Option{
+		client.WithTimeoutProvider(initRPCTimeoutContainer(param, dest, nacosClient, uniqueID, opts)),
+		client.WithCloseCallbacks(func() error {
+			// cancel the configuration listener when client is closed.
+			return nacosClient.DeregisterConfig(param, uniqueID)
+		}),
+	}
+}
+
+func initRPCTimeoutContainer(param core.ConfigParam, dest string,
+	nacosClient core.Client, uniqueID int64, opts utils.Options,
+) rpcinfo.TimeoutProvider {
+	rpcTimeoutContainer := rpctimeout.NewContainer()
+	expander := newMethodExpander(false, func(configs map[string]*rpctimeout.RPCTimeout, _ []string) {
+		rpcTimeoutContainer.NotifyPolicyChange(configs)
+	})
+	effective := map[string]*rpctimeout.RPCTimeout{}
+	// mu serializes the callbacks, the startup load and the listener may deliver concurrently.
+	var mu sync.Mutex
+
+	onChangeCallback := func(data string, parser core.ConfigParser) {
+		mu.Lock()
+		defer mu.Unlock()
+		configs := map[string]*rpctimeout.RPCTimeout{}
+		opts.Strict(rpcTimeoutConfigName, parser)
+		err := parser.Decode(param.Type, data, &configs)
+		if err != nil {
+			klog.Warnf("[nacos] %s client nacos rpc timeout: unmarshal data %s failed: %s, skip...", dest, data, err)
+			return
+		}
+		if err = opts.ValidateSchema(rpcTimeoutConfigName, param.Type, data, parser); err != nil {
+			klog.Warnf("[nacos] %s client nacos rpc timeout: data %s mismatches the schema: %s, skip...", dest, data, err)
+			core.Reject(parser, err)
+			return
+		}
+		if err = validate(rpcTimeoutConfigName, configs, &opts); err != nil {
+			klog.Warnf("[nacos] %s client nacos rpc timeout: invalid data %s: %s, skip...", dest, data, err)
+			core.Reject(parser, err)
+			return
+		}
+		matcher, err := compileMethodKeys(configs)
+		if err != nil {
+			klog.Warnf("[nacos] %s client nacos rpc timeout: invalid data %s: %s, skip...", dest, data, err)
+			core.Reject(parser, err)
+			return
+		}
+		if opts.DryRun("client", rpcTimeoutConfigName, dest, parser, effective, configs) {
+			return
+		}
+		expander.update(configs, matcher)
+		effective = configs
+	}
+
+	nacosClient.RegisterConfigCallback(param, onChangeCallback, uniqueID)
+
+	return &expandedTimeoutProvider{container: rpcTimeoutContainer, expander: expander}
+}
+
+// expandedTimeoutProvider records the called methods before looking up their timeouts.
+type expandedTimeoutProvider struct {
+	container *rpctimeout.Container
+	expander  *methodExpander[*rpctimeout.RPCTimeout]
+}
+
+// Timeouts implements rpcinfo.TimeoutProvider.
+func (p *expandedTimeoutProvider) Timeouts(ri rpcinfo.RPCInfo) rpcinfo.Timeouts {
+	p.expander.observe(ri.Invocation().MethodName())
+	return p.container.Timeouts(ri)
+}
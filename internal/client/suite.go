@@ -16,36 +16,35 @@ package client
 
 import (
 	"github.com/cloudwego/kitex/client"
-	"github.com/kitex-contrib/config-nacos/nacos"
-	"github.com/kitex-contrib/config-nacos/utils"
+	"github.com/kitex-contrib/config-nacos/core"
+	"github.com/kitex-contrib/config-nacos/internal/utils"
 )
 
 const (
 	retryConfigName          = "retry"
 	rpcTimeoutConfigName     = "rpc_timeout"
 	circuitBreakerConfigName = "circuit_break"
-	degradationName          = "degradation"
+	// the instance circuit breaker is configured on the same suite as the service one.
+	instanceCircuitBreakerConfigName = "instance_circuit_break"
+	degradationName                  = "degradation"
 )
 
 // NacosClientSuite nacos client config suite, configure retry timeout limit and circuitbreak dynamically from nacos.
 type NacosClientSuite struct {
-	nacosClient nacos.Client
+	nacosClient core.Client
 	service     string
 	client      string
 	opts        utils.Options
 }
 
 // NewSuite service is the destination service name and client is the local identity.
-func NewSuite(service, client string, cli nacos.Client, opts ...utils.Option) *NacosClientSuite {
-	su := &NacosClientSuite{
+func NewSuite(service, client string, cli core.Client, opts utils.Options) *NacosClientSuite {
+	return &NacosClientSuite{
 		service:     service,
 		client:      client,
 		nacosClient: cli,
+		opts:        opts,
 	}
-	for _, f := range opts {
-		f.Apply(&su.opts)
-	}
-	return su
 }
 
 // Options return a list client.Option
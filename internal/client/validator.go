@@ -0,0 +1,264 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cloudwego/kitex/pkg/circuitbreak"
+	"github.com/cloudwego/kitex/pkg/retry"
+	"github.com/cloudwego/kitex/pkg/rpctimeout"
+
+	"github.com/kitex-contrib/config-nacos/internal/utils"
+	"github.com/kitex-contrib/config-nacos/pkg/degradation"
+	retrypolicy "github.com/kitex-contrib/config-nacos/pkg/retry"
+)
+
+// keep consistent with the limits of kitex retryer.
+const (
+	maxFailureRetryTimes = 5
+	maxBackupRetryTimes  = 2
+	maxMixedRetryTimes   = 3
+)
+
+var builtinValidators = map[string]utils.Validator{
+	retryConfigName:                  utils.ValidatorFunc(validateRetry),
+	rpcTimeoutConfigName:             utils.ValidatorFunc(validateRPCTimeout),
+	circuitBreakerConfigName:         utils.ValidatorFunc(validateCircuitBreaker),
+	instanceCircuitBreakerConfigName: utils.ValidatorFunc(validateInstanceCircuitBreaker),
+	degradationName:                  utils.ValidatorFunc(validateDegradation),
+}
+
+// validate runs the built-in validator of the category and then the custom ones.
+func validate(category string, config interface{}, opts *utils.Options) error {
+	if v, ok := builtinValidators[category]; ok {
+		if err := v.Validate(config); err != nil {
+			return err
+		}
+	}
+	return opts.Validate(category, config)
+}
+
+func validateRetry(config interface{}) error {
+	rcs, ok := config.(*retrypolicy.Config)
+	if !ok {
+		return fmt.Errorf("unexpected retry config type %T", config)
+	}
+	if rcs.Container != nil {
+		if err := rcs.Container.Validate(); err != nil {
+			return fmt.Errorf("%s: %w", retrypolicy.ContainerKey, err)
+		}
+	}
+	for method, policy := range rcs.Policies {
+		if policy == nil {
+			return fmt.Errorf("policy for method %s: policy must not be empty", method)
+		}
+		if err := validateRetryPolicy(&policy.Policy); err != nil {
+			return fmt.Errorf("policy for method %s: %w", method, err)
+		}
+		if err := validateResultRetry(&policy.Policy, policy.ResultRetry); err != nil {
+			return fmt.Errorf("result_retry for method %s: %w", method, err)
+		}
+	}
+	return nil
+}
+
+func validateResultRetry(policy *retry.Policy, rr *retrypolicy.ResultRetry) error {
+	if rr == nil {
+		return nil
+	}
+	if policy.Type != retry.FailureType && policy.Type != retry.MixedType {
+		return errors.New("result_retry works with the failure and mixed policies only")
+	}
+	return rr.Validate()
+}
+
+func validateRetryPolicy(policy *retry.Policy) error {
+	if policy == nil {
+		return errors.New("policy must not be empty")
+	}
+	set := 0
+	for _, p := range []bool{policy.FailurePolicy != nil, policy.BackupPolicy != nil, policy.MixedPolicy != nil} {
+		if p {
+			set++
+		}
+	}
+	if set > 1 {
+		return errors.New("only one of FailurePolicy, BackupPolicy and MixedPolicy can be set")
+	}
+	if set == 0 {
+		return errors.New("FailurePolicy, BackupPolicy and MixedPolicy must not be empty at same time")
+	}
+	switch policy.Type {
+	case retry.FailureType:
+		if policy.FailurePolicy == nil {
+			return errors.New("type is failure but FailurePolicy is empty")
+		}
+		return validateFailurePolicy(policy.FailurePolicy)
+	case retry.BackupType:
+		if policy.BackupPolicy == nil {
+			return errors.New("type is backup but BackupPolicy is empty")
+		}
+		return validateBackupPolicy(policy.BackupPolicy)
+	case retry.MixedType:
+		if policy.MixedPolicy == nil {
+			return errors.New("type is mixed but MixedPolicy is empty")
+		}
+		return validateMixedPolicy(policy.MixedPolicy)
+	default:
+		return fmt.Errorf("unsupported type %d", policy.Type)
+	}
+}
+
+func validateFailurePolicy(p *retry.FailurePolicy) error {
+	if err := validateMaxRetryTimes(p.StopPolicy.MaxRetryTimes, maxFailureRetryTimes); err != nil {
+		return err
+	}
+	return validateBackOffPolicy(p.BackOffPolicy)
+}
+
+func validateBackupPolicy(p *retry.BackupPolicy) error {
+	if p.RetryDelayMS == 0 {
+		return errors.New("retry_delay_ms of BackupPolicy must be positive")
+	}
+	return validateMaxRetryTimes(p.StopPolicy.MaxRetryTimes, maxBackupRetryTimes)
+}
+
+func validateMixedPolicy(p *retry.MixedPolicy) error {
+	if p.RetryDelayMS == 0 {
+		return errors.New("retry_delay_ms of MixedPolicy must be positive")
+	}
+	if err := validateMaxRetryTimes(p.StopPolicy.MaxRetryTimes, maxMixedRetryTimes); err != nil {
+		return err
+	}
+	return validateBackOffPolicy(p.BackOffPolicy)
+}
+
+func validateMaxRetryTimes(times, max int) error {
+	if times < 0 || times > max {
+		return fmt.Errorf("max_retry_times %d out of range [0, %d]", times, max)
+	}
+	return nil
+}
+
+func validateBackOffPolicy(p *retry.BackOffPolicy) error {
+	if p == nil {
+		return nil
+	}
+	switch p.BackOffType {
+	case retry.NoneBackOffType:
+	case retry.FixedBackOffType:
+		if p.CfgItems[retry.FixMSBackOffCfgKey] <= 0 {
+			return errors.New("fixed backoff requires positive fix_ms")
+		}
+	case retry.RandomBackOffType:
+		minMS, maxMS := p.CfgItems[retry.MinMSBackOffCfgKey], p.CfgItems[retry.MaxMSBackOffCfgKey]
+		if minMS < 0 || maxMS <= minMS {
+			return fmt.Errorf("random backoff requires 0 <= min_ms < max_ms, got min_ms=%v max_ms=%v", minMS, maxMS)
+		}
+	default:
+		return fmt.Errorf("unsupported backoff_type %q", p.BackOffType)
+	}
+	return nil
+}
+
+func validateRPCTimeout(config interface{}) error {
+	configs, ok := config.(map[string]*rpctimeout.RPCTimeout)
+	if !ok {
+		return fmt.Errorf("unexpected rpc timeout config type %T", config)
+	}
+	for method, c := range configs {
+		if c == nil {
+			return fmt.Errorf("rpc timeout for method %s must not be empty", method)
+		}
+		if c.RPCTimeoutMS < 0 || c.ConnTimeoutMS < 0 {
+			return fmt.Errorf("rpc timeout for method %s must not be negative", method)
+		}
+		if c.RPCTimeoutMS > 0 && c.ConnTimeoutMS > c.RPCTimeoutMS {
+			return fmt.Errorf("conn_timeout_ms %d of method %s is greater than rpc_timeout_ms %d",
+				c.ConnTimeoutMS, method, c.RPCTimeoutMS)
+		}
+	}
+	return nil
+}
+
+func validateCircuitBreaker(config interface{}) error {
+	configs, ok := config.(map[string]circuitbreak.CBConfig)
+	if !ok {
+		return fmt.Errorf("unexpected circuit breaker config type %T", config)
+	}
+	for method, c := range configs {
+		if err := validateCBConfig(c); err != nil {
+			return fmt.Errorf("circuit breaker for method %s: %w", method, err)
+		}
+	}
+	return nil
+}
+
+func validateInstanceCircuitBreaker(config interface{}) error {
+	c, ok := config.(circuitbreak.CBConfig)
+	if !ok {
+		return fmt.Errorf("unexpected instance circuit breaker config type %T", config)
+	}
+	return validateCBConfig(c)
+}
+
+func validateCBConfig(c circuitbreak.CBConfig) error {
+	if !c.Enable {
+		return nil
+	}
+	if c.ErrRate <= 0 || c.ErrRate > 1 {
+		return fmt.Errorf("err_rate %v out of range (0, 1]", c.ErrRate)
+	}
+	if c.MinSample <= 0 {
+		return fmt.Errorf("min_sample %d must be positive", c.MinSample)
+	}
+	return nil
+}
+
+func validateDegradation(config interface{}) error {
+	configs, ok := config.(degradation.Configs)
+	if !ok {
+		return fmt.Errorf("unexpected degradation config type %T", config)
+	}
+	for method, c := range configs {
+		if c == nil {
+			return fmt.Errorf("degradation for method %s must not be empty", method)
+		}
+		if c.Percentage < 0 || c.Percentage > 100 {
+			return fmt.Errorf("percentage %d of method %s out of range [0, 100]", c.Percentage, method)
+		}
+		if c.Error != nil {
+			if err := c.Error.Validate(); err != nil {
+				return fmt.Errorf("degradation of method %s: %w", method, err)
+			}
+		}
+		if c.Schedule != nil {
+			if err := c.Schedule.Validate(); err != nil {
+				return fmt.Errorf("schedule of method %s: %w", method, err)
+			}
+		}
+		for i, r := range c.Rules {
+			if r == nil {
+				return fmt.Errorf("degradation rule %d of method %s must not be empty", i, method)
+			}
+			if r.Percentage < 0 || r.Percentage > 100 {
+				return fmt.Errorf("percentage %d of rule %d of method %s out of range [0, 100]", r.Percentage, i, method)
+			}
+		}
+	}
+	return nil
+}
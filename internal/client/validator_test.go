@@ -0,0 +1,166 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cloudwego/kitex/pkg/circuitbreak"
+	"github.com/cloudwego/kitex/pkg/retry"
+	"github.com/cloudwego/kitex/pkg/rpctimeout"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kitex-contrib/config-nacos/internal/utils"
+	"github.com/kitex-contrib/config-nacos/pkg/degradation"
+	retrypolicy "github.com/kitex-contrib/config-nacos/pkg/retry"
+)
+
+func TestValidateRetry(t *testing.T) {
+	failure := func(times int, bo *retry.BackOffPolicy) *retry.Policy {
+		return &retry.Policy{Enable: true, Type: retry.FailureType, FailurePolicy: &retry.FailurePolicy{
+			StopPolicy:    retry.StopPolicy{MaxRetryTimes: times},
+			BackOffPolicy: bo,
+		}}
+	}
+	wrap := func(p *retry.Policy) *retrypolicy.Policy {
+		if p == nil {
+			return nil
+		}
+		return &retrypolicy.Policy{Policy: *p}
+	}
+	opts := &utils.Options{}
+	valid := map[string]*retrypolicy.Policy{
+		"*": wrap(failure(3, &retry.BackOffPolicy{
+			BackOffType: retry.FixedBackOffType,
+			CfgItems:    map[retry.BackOffCfgKey]float64{retry.FixMSBackOffCfgKey: 50},
+		})),
+		"echo": wrap(&retry.Policy{Enable: true, Type: retry.BackupType, BackupPolicy: &retry.BackupPolicy{
+			RetryDelayMS: 100,
+			StopPolicy:   retry.StopPolicy{MaxRetryTimes: 2},
+		}}),
+		"ping": wrap(&retry.Policy{Enable: true, Type: retry.MixedType, MixedPolicy: &retry.MixedPolicy{
+			RetryDelayMS:  50,
+			FailurePolicy: retry.FailurePolicy{StopPolicy: retry.StopPolicy{MaxRetryTimes: 3}},
+		}}),
+	}
+	assert.Nil(t, validate(retryConfigName, &retrypolicy.Config{Policies: valid}, opts))
+
+	valid["*"].ResultRetry = &retrypolicy.ResultRetry{
+		ErrorTypes:     []string{"remote_or_network"},
+		BizStatusCodes: []int32{503},
+		RespFields:     []retrypolicy.FieldPredicate{{Field: "BaseResp.StatusCode", Op: "in", Value: []interface{}{503.0}}},
+	}
+	assert.Nil(t, validate(retryConfigName, &retrypolicy.Config{Policies: valid}, opts))
+	for _, rr := range []*retrypolicy.ResultRetry{
+		{ErrorTypes: []string{"unknown"}},
+		{RespFields: []retrypolicy.FieldPredicate{{Value: 1}}},
+		{RespFields: []retrypolicy.FieldPredicate{{Field: "code", Op: "like", Value: 1}}},
+		{RespFields: []retrypolicy.FieldPredicate{{Field: "code", Op: "in", Value: 1}}},
+	} {
+		valid["*"].ResultRetry = rr
+		assert.NotNil(t, validate(retryConfigName, &retrypolicy.Config{Policies: valid}, opts))
+	}
+	valid["*"].ResultRetry = nil
+	assert.NotNil(t, validate(retryConfigName, &retrypolicy.Config{
+		Container: &retrypolicy.Container{MaxRetryPercentage: 50},
+		Policies:  valid,
+	}, opts))
+	valid["echo"].ResultRetry = &retrypolicy.ResultRetry{BizStatusCodes: []int32{503}}
+	assert.NotNil(t, validate(retryConfigName, &retrypolicy.Config{Policies: valid}, opts))
+
+	invalids := []*retry.Policy{
+		nil,
+		{Type: retry.FailureType},
+		{Type: retry.FailureType, FailurePolicy: &retry.FailurePolicy{}, BackupPolicy: &retry.BackupPolicy{}},
+		{Type: retry.BackupType, FailurePolicy: &retry.FailurePolicy{}},
+		{Type: retry.BackupType, BackupPolicy: &retry.BackupPolicy{}},
+		failure(6, nil),
+		failure(-1, nil),
+		failure(1, &retry.BackOffPolicy{BackOffType: retry.FixedBackOffType}),
+		failure(1, &retry.BackOffPolicy{
+			BackOffType: retry.RandomBackOffType,
+			CfgItems:    map[retry.BackOffCfgKey]float64{retry.MinMSBackOffCfgKey: 50, retry.MaxMSBackOffCfgKey: 10},
+		}),
+		failure(1, &retry.BackOffPolicy{BackOffType: "linear"}),
+		{Type: retry.MixedType, FailurePolicy: &retry.FailurePolicy{}},
+		{Type: retry.MixedType, MixedPolicy: &retry.MixedPolicy{}},
+		{Type: retry.MixedType, MixedPolicy: &retry.MixedPolicy{
+			RetryDelayMS:  50,
+			FailurePolicy: retry.FailurePolicy{StopPolicy: retry.StopPolicy{MaxRetryTimes: 4}},
+		}},
+		{Type: retry.MixedType, MixedPolicy: &retry.MixedPolicy{RetryDelayMS: 50}, BackupPolicy: &retry.BackupPolicy{}},
+	}
+	for _, p := range invalids {
+		assert.NotNil(t, validate(retryConfigName, &retrypolicy.Config{Policies: map[string]*retrypolicy.Policy{"echo": wrap(p)}}, opts))
+	}
+}
+
+func TestValidateOthers(t *testing.T) {
+	opts := &utils.Options{}
+	assert.Nil(t, validate(rpcTimeoutConfigName, map[string]*rpctimeout.RPCTimeout{
+		"*": {RPCTimeoutMS: 1000, ConnTimeoutMS: 100},
+	}, opts))
+	assert.NotNil(t, validate(rpcTimeoutConfigName, map[string]*rpctimeout.RPCTimeout{
+		"*": {RPCTimeoutMS: 100, ConnTimeoutMS: 1000},
+	}, opts))
+	assert.NotNil(t, validate(rpcTimeoutConfigName, map[string]*rpctimeout.RPCTimeout{
+		"*": {RPCTimeoutMS: -1},
+	}, opts))
+
+	assert.Nil(t, validate(circuitBreakerConfigName, map[string]circuitbreak.CBConfig{
+		"echo": {Enable: true, ErrRate: 0.3, MinSample: 100},
+		"ping": {Enable: false},
+	}, opts))
+	assert.NotNil(t, validate(circuitBreakerConfigName, map[string]circuitbreak.CBConfig{
+		"echo": {Enable: true, ErrRate: 1.3, MinSample: 100},
+	}, opts))
+	assert.NotNil(t, validate(circuitBreakerConfigName, map[string]circuitbreak.CBConfig{
+		"echo": {Enable: true, ErrRate: 0.3},
+	}, opts))
+	assert.Nil(t, validate(instanceCircuitBreakerConfigName, circuitbreak.CBConfig{Enable: true, ErrRate: 0.3, MinSample: 100}, opts))
+	assert.NotNil(t, validate(instanceCircuitBreakerConfigName, circuitbreak.CBConfig{Enable: true, ErrRate: 0}, opts))
+
+	assert.Nil(t, validate(degradationName, degradation.Configs{"*": {Enable: true, Percentage: 100}}, opts))
+	assert.NotNil(t, validate(degradationName, degradation.Configs{"echo": {Enable: true, Percentage: 101}}, opts))
+	assert.NotNil(t, validate(degradationName, degradation.Configs{"echo": nil}, opts))
+	assert.Nil(t, validate(degradationName, degradation.Configs{"*": {Enable: true, Rules: []*degradation.Rule{{Percentage: 50}}}}, opts))
+	assert.NotNil(t, validate(degradationName, degradation.Configs{"*": {Enable: true, Rules: []*degradation.Rule{{Percentage: -1}}}}, opts))
+	assert.NotNil(t, validate(degradationName, degradation.Configs{"*": {Enable: true, Rules: []*degradation.Rule{nil}}}, opts))
+	assert.Nil(t, validate(degradationName, degradation.Configs{"*": {Enable: true, Error: &degradation.ErrorConfig{Kind: degradation.ErrorKindBizStatus, Code: 503}}}, opts))
+	assert.NotNil(t, validate(degradationName, degradation.Configs{"*": {Enable: true, Error: &degradation.ErrorConfig{Kind: degradation.ErrorKindBizStatus}}}, opts))
+	assert.NotNil(t, validate(degradationName, degradation.Configs{"*": {Enable: true, Error: &degradation.ErrorConfig{Kind: "unknown"}}}, opts))
+	assert.Nil(t, validate(degradationName, degradation.Configs{"*": {Enable: true, Schedule: &degradation.Schedule{
+		Daily: []*degradation.Window{{Start: "20:00", End: "02:00"}}, TimeZone: "Asia/Shanghai", RampUpMS: 600000,
+	}}}, opts))
+	assert.NotNil(t, validate(degradationName, degradation.Configs{"*": {Enable: true, Schedule: &degradation.Schedule{
+		Start: "2024-11-11T00:00:00+08:00", End: "2024-11-10T00:00:00+08:00",
+	}}}, opts))
+}
+
+func TestCustomValidator(t *testing.T) {
+	errCustom := errors.New("custom")
+	opts := &utils.Options{}
+	utils.WithValidator(degradationName, utils.ValidatorFunc(func(config interface{}) error {
+		if config.(degradation.Configs).Get("echo").Percentage > 50 {
+			return errCustom
+		}
+		return nil
+	})).Apply(opts)
+	assert.Nil(t, validate(degradationName, degradation.Configs{"*": {Enable: true, Percentage: 50}}, opts))
+	assert.Equal(t, errCustom, validate(degradationName, degradation.Configs{"*": {Enable: true, Percentage: 60}}, opts))
+	// the built-in validator runs first
+	assert.NotEqual(t, errCustom, validate(degradationName, degradation.Configs{"*": {Enable: true, Percentage: 101}}, opts))
+}
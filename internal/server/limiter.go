@@ -15,21 +15,21 @@
 package server
 
 import (
+	"sync"
 	"sync/atomic"
 
-	"github.com/kitex-contrib/config-nacos/v2/nacos"
-	"github.com/kitex-contrib/config-nacos/v2/utils"
-	"github.com/nacos-group/nacos-sdk-go/v2/vo"
-
 	"github.com/cloudwego/kitex/pkg/klog"
 	"github.com/cloudwego/kitex/pkg/limit"
 	"github.com/cloudwego/kitex/pkg/limiter"
 	"github.com/cloudwego/kitex/server"
+
+	"github.com/kitex-contrib/config-nacos/core"
+	"github.com/kitex-contrib/config-nacos/internal/utils"
 )
 
 // WithLimiter sets the limiter config from nacos configuration center.
-func WithLimiter(dest string, nacosClient nacos.Client, opts utils.Options) server.Option {
-	param, err := nacosClient.ServerConfigParam(&nacos.ConfigParamConfig{
+func WithLimiter(dest string, nacosClient core.Client, opts utils.Options) server.Option {
+	param, err := nacosClient.ServerConfigParam(&core.ConfigParamConfig{
 		Category:          limiterConfigName,
 		ServerServiceName: dest,
 	})
@@ -37,31 +37,48 @@ func WithLimiter(dest string, nacosClient nacos.Client, opts utils.Options) serv
 		panic(err)
 	}
 
-	for _, f := range opts.NacosCustomFunctions {
-		f(&param)
-	}
-	uniqueID := nacos.GetUniqueID()
+	uniqueID := core.GetUniqueID()
 	server.RegisterShutdownHook(func() {
 		nacosClient.DeregisterConfig(param, uniqueID)
 	})
-	return server.WithLimit(initLimitOptions(param, dest, nacosClient, uniqueID))
+	return server.WithLimit(initLimitOptions(param, dest, nacosClient, uniqueID, opts))
 }
 
-func initLimitOptions(param vo.ConfigParam, dest string, nacosClient nacos.Client, uniqueID int64) *limit.Option {
+func initLimitOptions(param core.ConfigParam, dest string, nacosClient core.Client, uniqueID int64, opts utils.Options) *limit.Option {
 	var updater atomic.Value
 	opt := &limit.Option{}
+	effective := &limiter.LimiterConfig{}
+	// mu serializes the callbacks, the startup load and the listener may deliver concurrently.
+	var mu sync.Mutex
 	opt.UpdateControl = func(u limit.Updater) {
 		klog.Debugf("[nacos] %s server nacos limiter updater init, config %v", dest, *opt)
 		u.UpdateLimit(opt)
 		updater.Store(u)
 	}
-	onChangeCallback := func(data string, parser nacos.ConfigParser) {
+	onChangeCallback := func(data string, parser core.ConfigParser) {
+		mu.Lock()
+		defer mu.Unlock()
 		lc := &limiter.LimiterConfig{}
+		opts.Strict(limiterConfigName, parser)
 		err := parser.Decode(param.Type, data, lc)
 		if err != nil {
 			klog.Warnf("[nacos] %s server nacos limiter config: unmarshal data %s failed: %s, skip...", dest, data, err)
 			return
 		}
+		if err = opts.ValidateSchema(limiterConfigName, param.Type, data, parser); err != nil {
+			klog.Warnf("[nacos] %s server nacos limiter config: data %s mismatches the schema: %s, skip...", dest, data, err)
+			core.Reject(parser, err)
+			return
+		}
+		if err = validate(limiterConfigName, lc, &opts); err != nil {
+			klog.Warnf("[nacos] %s server nacos limiter config: invalid data %s: %s, skip...", dest, data, err)
+			core.Reject(parser, err)
+			return
+		}
+		if opts.DryRun("server", limiterConfigName, dest, parser, effective, lc) {
+			return
+		}
+		effective = lc
 		opt.MaxConnections = int(lc.ConnectionLimit)
 		opt.MaxQPS = int(lc.QPSLimit)
 		u := updater.Load()
@@ -16,8 +16,8 @@ package server
 
 import (
 	"github.com/cloudwego/kitex/server"
-	"github.com/kitex-contrib/config-nacos/nacos"
-	"github.com/kitex-contrib/config-nacos/utils"
+	"github.com/kitex-contrib/config-nacos/core"
+	"github.com/kitex-contrib/config-nacos/internal/utils"
 )
 
 const (
@@ -26,21 +26,18 @@ const (
 
 // NacosServerSuite nacos server config suite, configure limiter config dynamically from nacos.
 type NacosServerSuite struct {
-	nacosClient nacos.Client
+	nacosClient core.Client
 	service     string
 	opts        utils.Options
 }
 
 // NewSuite service is the destination service.
-func NewSuite(service string, cli nacos.Client, opts ...utils.Option) *NacosServerSuite {
-	su := &NacosServerSuite{
+func NewSuite(service string, cli core.Client, opts utils.Options) *NacosServerSuite {
+	return &NacosServerSuite{
 		service:     service,
 		nacosClient: cli,
+		opts:        opts,
 	}
-	for _, opt := range opts {
-		opt.Apply(&su.opts)
-	}
-	return su
 }
 
 // Options return a list client.Option
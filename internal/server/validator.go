@@ -0,0 +1,51 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"fmt"
+
+	"github.com/cloudwego/kitex/pkg/limiter"
+
+	"github.com/kitex-contrib/config-nacos/internal/utils"
+)
+
+var builtinValidators = map[string]utils.Validator{
+	limiterConfigName: utils.ValidatorFunc(validateLimiter),
+}
+
+// validate runs the built-in validator of the category and then the custom ones.
+func validate(category string, config interface{}, opts *utils.Options) error {
+	if v, ok := builtinValidators[category]; ok {
+		if err := v.Validate(config); err != nil {
+			return err
+		}
+	}
+	return opts.Validate(category, config)
+}
+
+func validateLimiter(config interface{}) error {
+	lc, ok := config.(*limiter.LimiterConfig)
+	if !ok {
+		return fmt.Errorf("unexpected limiter config type %T", config)
+	}
+	if lc.ConnectionLimit < 0 {
+		return fmt.Errorf("connection_limit %d must not be negative", lc.ConnectionLimit)
+	}
+	if lc.QPSLimit < 0 {
+		return fmt.Errorf("qps_limit %d must not be negative", lc.QPSLimit)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/cloudwego/kitex/pkg/klog"
+
+	"github.com/kitex-contrib/config-nacos/core"
+)
+
+// DryRunAll put all the categories of the suite in dry-run mode.
+const DryRunAll = "*"
+
+type dryRunOption struct {
+	categories []string
+}
+
+// Apply implements Option.
+func (o *dryRunOption) Apply(opts *Options) {
+	if opts.DryRunCategories == nil {
+		opts.DryRunCategories = map[string]bool{}
+	}
+	for _, c := range o.categories {
+		opts.DryRunCategories[c] = true
+	}
+}
+
+// WithDryRun puts the categories in dry-run mode, all the categories if empty. The new config of a
+// dry-run category is decoded, validated and diffed against the effective one, but not applied.
+func WithDryRun(categories ...string) Option {
+	if len(categories) == 0 {
+		categories = []string{DryRunAll}
+	}
+	return &dryRunOption{categories: categories}
+}
+
+// IsDryRun reports whether the category is in dry-run mode.
+func (o *Options) IsDryRun(category string) bool {
+	return o.DryRunCategories[DryRunAll] || o.DryRunCategories[category]
+}
+
+// DryRun reports the difference between the effective and the new config if the category is in dry-run
+// mode, the config must not be applied when it returns true. The side is "client" or "server" for the log.
+// The config loaded at startup is not applied either, the suite keeps the code defaults and the diff
+// is against them.
+func (o *Options) DryRun(side, category, dest string, parser core.ConfigParser, effective, config interface{}) bool {
+	if !o.IsDryRun(category) {
+		return false
+	}
+	diff := Diff(effective, config)
+	klog.Infof("[nacos] %s %s nacos %s dry-run, the config is not applied, diff: %v", dest, side, category, diff)
+	core.DryRun(parser, diff)
+	return true
+}
+
+// Diff returns the differences from old to new by json path. Every line is prefixed with
+// "+" for the added path, "-" for the removed path and "~" for the changed value.
+func Diff(old, new interface{}) []string {
+	var out []string
+	diffValue("", normalize(old), normalize(new), &out)
+	sort.Strings(out)
+	return out
+}
+
+// normalize converts the config to the generic json tree.
+func normalize(v interface{}) interface{} {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	var tree interface{}
+	if err = json.Unmarshal(buf, &tree); err != nil {
+		return string(buf)
+	}
+	return tree
+}
+
+func diffValue(path string, old, new interface{}, out *[]string) {
+	om, ook := old.(map[string]interface{})
+	nm, nok := new.(map[string]interface{})
+	if ook && nok {
+		for k, ov := range om {
+			nv, ok := nm[k]
+			if !ok {
+				*out = append(*out, fmt.Sprintf("- %s: %s", joinPath(path, k), marshal(ov)))
+				continue
+			}
+			diffValue(joinPath(path, k), ov, nv, out)
+		}
+		for k, nv := range nm {
+			if _, ok := om[k]; !ok {
+				*out = append(*out, fmt.Sprintf("+ %s: %s", joinPath(path, k), marshal(nv)))
+			}
+		}
+		return
+	}
+	if o, n := marshal(old), marshal(new); o != n {
+		if path == "" {
+			path = "."
+		}
+		*out = append(*out, fmt.Sprintf("~ %s: %s -> %s", path, o, n))
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func marshal(v interface{}) string {
+	buf, _ := json.Marshal(v)
+	return string(buf)
+}
@@ -0,0 +1,60 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	type timeout struct {
+		RPCTimeoutMS  int `json:"rpc_timeout_ms"`
+		ConnTimeoutMS int `json:"conn_timeout_ms"`
+	}
+	old := map[string]*timeout{
+		"*":    {RPCTimeoutMS: 1000, ConnTimeoutMS: 100},
+		"echo": {RPCTimeoutMS: 500},
+	}
+	new := map[string]*timeout{
+		"*":    {RPCTimeoutMS: 2000, ConnTimeoutMS: 100},
+		"ping": {RPCTimeoutMS: 300},
+	}
+	assert.Equal(t, []string{
+		`+ ping: {"conn_timeout_ms":0,"rpc_timeout_ms":300}`,
+		`- echo: {"conn_timeout_ms":0,"rpc_timeout_ms":500}`,
+		`~ *.rpc_timeout_ms: 1000 -> 2000`,
+	}, Diff(old, new))
+	assert.Nil(t, Diff(old, old))
+	assert.Equal(t, []string{`~ .: null -> {"*":null}`}, Diff(nil, map[string]*timeout{"*": nil}))
+}
+
+func TestDryRunOption(t *testing.T) {
+	opts := &Options{}
+	assert.False(t, opts.IsDryRun("retry"))
+	WithDryRun("retry").Apply(opts)
+	assert.True(t, opts.IsDryRun("retry"))
+	assert.False(t, opts.IsDryRun("limit"))
+	WithDryRun().Apply(opts)
+	assert.True(t, opts.IsDryRun("limit"))
+}
+
+func TestDryRun(t *testing.T) {
+	opts := &Options{}
+	WithDryRun("limit").Apply(opts)
+	assert.True(t, opts.DryRun("server", "limit", "svc", nil, nil, 1))
+	assert.False(t, opts.DryRun("server", "retry", "svc", nil, nil, 1))
+}
@@ -0,0 +1,108 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	// WildcardMethod is the key matching any method.
+	WildcardMethod = "*"
+	// RegexpMethodPrefix marks a method key as a regular expression, e.g. "re:^List.+$".
+	RegexpMethodPrefix = "re:"
+)
+
+type methodRegexp struct {
+	key string
+	re  *regexp.Regexp
+}
+
+// MethodMatcher resolves a method name to the key of a per-method config. The precedence is
+// exact name > longest glob (e.g. "Get*") > regular expression in key order > wildcard "*".
+type MethodMatcher struct {
+	exact    Set
+	globs    []string
+	regexps  []methodRegexp
+	wildcard bool
+}
+
+// NewMethodMatcher compiles the keys of a per-method config.
+func NewMethodMatcher(keys []string) (*MethodMatcher, error) {
+	m := &MethodMatcher{exact: Set{}}
+	for _, key := range keys {
+		switch {
+		case key == WildcardMethod:
+			m.wildcard = true
+		case strings.HasPrefix(key, RegexpMethodPrefix):
+			re, err := regexp.Compile(strings.TrimPrefix(key, RegexpMethodPrefix))
+			if err != nil {
+				return nil, fmt.Errorf("invalid method pattern %q: %w", key, err)
+			}
+			m.regexps = append(m.regexps, methodRegexp{key: key, re: re})
+		case IsMethodPattern(key):
+			if _, err := path.Match(key, ""); err != nil {
+				return nil, fmt.Errorf("invalid method pattern %q: %w", key, err)
+			}
+			m.globs = append(m.globs, key)
+		default:
+			m.exact[key] = true
+		}
+	}
+	sort.Slice(m.globs, func(i, j int) bool {
+		if len(m.globs[i]) != len(m.globs[j]) {
+			return len(m.globs[i]) > len(m.globs[j])
+		}
+		return m.globs[i] < m.globs[j]
+	})
+	sort.Slice(m.regexps, func(i, j int) bool {
+		return m.regexps[i].key < m.regexps[j].key
+	})
+	return m, nil
+}
+
+// IsMethodPattern reports whether the key is a glob or regular expression rather than a method name.
+func IsMethodPattern(key string) bool {
+	return key != WildcardMethod &&
+		(strings.HasPrefix(key, RegexpMethodPrefix) || strings.ContainsAny(key, "*?["))
+}
+
+// Match returns the key of the config that applies to the method.
+func (m *MethodMatcher) Match(method string) (string, bool) {
+	if m == nil {
+		return "", false
+	}
+	if m.exact[method] {
+		return method, true
+	}
+	for _, glob := range m.globs {
+		if ok, _ := path.Match(glob, method); ok {
+			return glob, true
+		}
+	}
+	for _, r := range m.regexps {
+		if r.re.MatchString(method) {
+			return r.key, true
+		}
+	}
+	if m.wildcard {
+		return WildcardMethod, true
+	}
+	return "", false
+}
@@ -0,0 +1,51 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMethodMatcher(t *testing.T) {
+	m, err := NewMethodMatcher([]string{"*", "Get*", "GetUser*", "re:^List.+$", "re:^GetUserInfo$", "Echo"})
+	assert.Nil(t, err)
+
+	for method, want := range map[string]string{
+		"Echo":        "Echo",
+		"GetUserInfo": "GetUser*",
+		"GetOrder":    "Get*",
+		"ListOrders":  "re:^List.+$",
+		"List":        "*",
+		"Ping":        "*",
+	} {
+		got, ok := m.Match(method)
+		assert.True(t, ok, method)
+		assert.Equal(t, want, got, method)
+	}
+
+	m, err = NewMethodMatcher([]string{"Get?"})
+	assert.Nil(t, err)
+	_, ok := m.Match("Gets")
+	assert.True(t, ok)
+	_, ok = m.Match("GetUser")
+	assert.False(t, ok)
+
+	_, err = NewMethodMatcher([]string{"re:("})
+	assert.NotNil(t, err)
+	_, err = NewMethodMatcher([]string{"Get["})
+	assert.NotNil(t, err)
+}
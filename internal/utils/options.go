@@ -0,0 +1,33 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+// Option is used to custom Options.
+type Option interface {
+	Apply(*Options)
+}
+
+// Options the governance options of the suites shared by the v1 and v2 modules, the custom functions
+// are applied by the nacos clients adapting to the core.
+type Options struct {
+	// Validators the custom validators of every category, keyed by the category name.
+	Validators map[string][]Validator
+	// DryRunCategories the categories in dry-run mode, DryRunAll for all the categories.
+	DryRunCategories map[string]bool
+	// SchemaValidation validates the payloads against the JSON Schemas of the categories.
+	SchemaValidation bool
+	// StrictCategories the categories decoded in strict mode, StrictAll for all the categories.
+	StrictCategories map[string]bool
+}
@@ -0,0 +1,49 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"github.com/kitex-contrib/config-nacos/core"
+	"github.com/kitex-contrib/config-nacos/pkg/schema"
+)
+
+type schemaValidationOption struct{}
+
+// Apply implements Option.
+func (o *schemaValidationOption) Apply(opts *Options) {
+	opts.SchemaValidation = true
+}
+
+// WithSchemaValidation validates the payloads against the JSON Schemas of the categories before applying,
+// so the unknown fields like typos, which are ignored by the decoder, reject the payload.
+func WithSchemaValidation() Option {
+	return &schemaValidationOption{}
+}
+
+// ValidateSchema validates the payload against the schema of the category if the schema validation is enabled.
+func (o *Options) ValidateSchema(category, kind, data string, parser core.ConfigParser) error {
+	if !o.SchemaValidation {
+		return nil
+	}
+	s, ok := schema.For(category)
+	if !ok {
+		return nil
+	}
+	var tree interface{}
+	if err := parser.Decode(kind, data, &tree); err != nil {
+		return err
+	}
+	return s.Validate(tree)
+}
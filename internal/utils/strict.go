@@ -0,0 +1,55 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import "github.com/kitex-contrib/config-nacos/core"
+
+// StrictAll decodes all the categories of the suite in strict mode.
+const StrictAll = "*"
+
+type strictOption struct {
+	categories []string
+}
+
+// Apply implements Option.
+func (o *strictOption) Apply(opts *Options) {
+	if opts.StrictCategories == nil {
+		opts.StrictCategories = map[string]bool{}
+	}
+	for _, c := range o.categories {
+		opts.StrictCategories[c] = true
+	}
+}
+
+// WithStrictDecoding decodes the categories in strict mode, all the categories if empty. The payloads
+// with unknown or duplicate fields are rejected with the path of the field and the previous config is kept.
+func WithStrictDecoding(categories ...string) Option {
+	if len(categories) == 0 {
+		categories = []string{StrictAll}
+	}
+	return &strictOption{categories: categories}
+}
+
+// IsStrict reports whether the category is decoded in strict mode.
+func (o *Options) IsStrict(category string) bool {
+	return o.StrictCategories[StrictAll] || o.StrictCategories[category]
+}
+
+// Strict puts the parser in strict mode if the category is decoded in strict mode.
+func (o *Options) Strict(category string, parser core.ConfigParser) {
+	if o.IsStrict(category) {
+		core.Strict(parser)
+	}
+}
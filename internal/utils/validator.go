@@ -0,0 +1,57 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+// Validator validates the decoded config of a category before it is applied.
+// The invalid config is rejected whole and the previous config is kept.
+type Validator interface {
+	Validate(config interface{}) error
+}
+
+// ValidatorFunc is an adapter to allow the use of ordinary functions as Validator.
+type ValidatorFunc func(config interface{}) error
+
+// Validate calls f(config).
+func (f ValidatorFunc) Validate(config interface{}) error {
+	return f(config)
+}
+
+type validatorOption struct {
+	category  string
+	validator Validator
+}
+
+// Apply implements Option.
+func (o *validatorOption) Apply(opts *Options) {
+	if opts.Validators == nil {
+		opts.Validators = map[string][]Validator{}
+	}
+	opts.Validators[o.category] = append(opts.Validators[o.category], o.validator)
+}
+
+// WithValidator registers the validator for the category, which runs after the built-in one.
+func WithValidator(category string, v Validator) Option {
+	return &validatorOption{category: category, validator: v}
+}
+
+// Validate runs the validators registered for the category in order.
+func (o *Options) Validate(category string, config interface{}) error {
+	for _, v := range o.Validators[category] {
+		if err := v.Validate(config); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -4,7 +4,7 @@
 // you may not use this file except in compliance with the License.
 // You may obtain a copy of the License at
 //
-//     http://www.apache.org/licenses/LICENSE-2.0
+//	http://www.apache.org/licenses/LICENSE-2.0
 //
 // Unless required by applicable law or agreed to in writing, software
 // distributed under the License is distributed on an "AS IS" BASIS,
@@ -17,43 +17,10 @@ package nacos
 import (
 	"github.com/nacos-group/nacos-sdk-go/common/logger"
 
-	"github.com/cloudwego/kitex/pkg/klog"
+	"github.com/kitex-contrib/config-nacos/core"
 )
 
-type customNacosLogger struct{}
-
+// NewCustomNacosLogger returns the logger forwarding the logs of the nacos sdk to klog.
 func NewCustomNacosLogger() logger.Logger {
-	return customNacosLogger{}
-}
-
-func (m customNacosLogger) Info(args ...interface{}) {
-	klog.Info(args...)
-}
-
-func (m customNacosLogger) Warn(args ...interface{}) {
-	klog.Warn(args...)
-}
-
-func (m customNacosLogger) Error(args ...interface{}) {
-	klog.Error(args...)
-}
-
-func (m customNacosLogger) Debug(args ...interface{}) {
-	klog.Debug(args)
-}
-
-func (m customNacosLogger) Infof(fmt string, args ...interface{}) {
-	klog.Infof(fmt, args...)
-}
-
-func (m customNacosLogger) Warnf(fmt string, args ...interface{}) {
-	klog.Warnf(fmt, args...)
-}
-
-func (m customNacosLogger) Errorf(fmt string, args ...interface{}) {
-	klog.Errorf(fmt, args...)
-}
-
-func (m customNacosLogger) Debugf(fmt string, args ...interface{}) {
-	klog.Debugf(fmt, args...)
+	return core.NacosLogger{}
 }
@@ -15,56 +15,106 @@
 package nacos
 
 import (
-	"fmt"
-
 	"github.com/nacos-group/nacos-sdk-go/vo"
-	"sigs.k8s.io/yaml"
-)
 
-const (
-	NacosDefaultServerAddr   = "127.0.0.1"
-	NacosDefaultPort         = 8848
-	NacosDefaultConfigGroup  = "DEFAULT_GROUP"
-	NacosDefaultClientDataID = "{{.ClientServiceName}}.{{.ServerServiceName}}.{{.Category}}"
-	NacosDefaultServerDataID = "{{.ServerServiceName}}.{{.Category}}"
+	"github.com/kitex-contrib/config-nacos/core"
 )
 
 const (
-	defaultContent = ""
+	NacosDefaultServerAddr   = core.NacosDefaultServerAddr
+	NacosDefaultPort         = core.NacosDefaultPort
+	NacosDefaultConfigGroup  = core.NacosDefaultConfigGroup
+	NacosDefaultClientDataID = core.NacosDefaultClientDataID
+	NacosDefaultServerDataID = core.NacosDefaultServerDataID
 )
 
+// TOML the config type of TOML, which is not defined by the nacos sdk.
+const TOML vo.ConfigType = core.TOML
+
 // CustomFunction use for customize the config parameters.
 type CustomFunction func(*vo.ConfigParam)
 
 // ConfigParamConfig use for render the dataId or group info by go template, ref: https://pkg.go.dev/text/template
 // The fixed key shows as below.
-type ConfigParamConfig struct {
-	Category          string
-	ClientServiceName string
-	ServerServiceName string
-}
-
-var _ ConfigParser = &parser{}
+type ConfigParamConfig = core.ConfigParamConfig
 
 // ConfigParser the parser for nacos config.
 type ConfigParser interface {
 	Decode(kind vo.ConfigType, data string, config interface{}) error
 }
 
-type parser struct{}
+// ConfigParserFunc is an adapter to allow the use of ordinary functions as ConfigParser.
+type ConfigParserFunc func(kind vo.ConfigType, data string, config interface{}) error
+
+// Decode calls f(kind, data, config).
+func (f ConfigParserFunc) Decode(kind vo.ConfigType, data string, config interface{}) error {
+	return f(kind, data, config)
+}
+
+// RegisterParser registers the parser of the config type for the default parser, the built-in
+// parsers of json, yaml, toml and properties can be replaced as well.
+func RegisterParser(kind vo.ConfigType, parser ConfigParser) {
+	core.RegisterParser(string(kind), toCoreParser(parser))
+}
+
+// DetectKind detects the config type by the dataId suffix, then by the content. It's used when
+// the type of the config param is empty or text.
+func DetectKind(dataID, data string) vo.ConfigType {
+	return vo.ConfigType(core.DetectKind(dataID, data))
+}
+
+// Reject marks the content delivered with the parser as rejected, see core.Reject.
+func Reject(parser ConfigParser, err error) {
+	core.Reject(toCoreParser(parser), err)
+}
 
-// Decode decodes the data to struct in specified format.
-func (p *parser) Decode(kind vo.ConfigType, data string, config interface{}) error {
-	switch kind {
-	case vo.YAML, vo.JSON:
-		// since YAML is a superset of JSON, it can parse JSON using a YAML parser
-		return yaml.Unmarshal([]byte(data), config)
-	default:
-		return fmt.Errorf("unsupported config data type %s", kind)
+// DryRun marks the content delivered with the parser as dry run, see core.DryRun.
+func DryRun(parser ConfigParser, diff []string) {
+	core.DryRun(toCoreParser(parser), diff)
+}
+
+// Strict decodes the content delivered with the parser in strict mode, see core.Strict.
+func Strict(parser ConfigParser) {
+	core.Strict(toCoreParser(parser))
+}
+
+// coreParser the parser of the core seen as a ConfigParser.
+type coreParser struct {
+	parser core.ConfigParser
+}
+
+// Decode implements ConfigParser.
+func (p *coreParser) Decode(kind vo.ConfigType, data string, config interface{}) error {
+	return p.parser.Decode(string(kind), data, config)
+}
+
+// sdkParser the ConfigParser seen as a parser of the core.
+type sdkParser struct {
+	parser ConfigParser
+}
+
+// Decode implements core.ConfigParser.
+func (p *sdkParser) Decode(kind, data string, config interface{}) error {
+	return p.parser.Decode(vo.ConfigType(kind), data, config)
+}
+
+// toCoreParser unwraps the parsers delivered by the core, so the core can recognize them.
+func toCoreParser(parser ConfigParser) core.ConfigParser {
+	switch p := parser.(type) {
+	case nil:
+		return nil
+	case *coreParser:
+		return p.parser
 	}
+	return &sdkParser{parser: parser}
 }
 
-// DefaultConfigParse default nacos config parser.
-func defaultConfigParse() ConfigParser {
-	return &parser{}
+func fromCoreParser(parser core.ConfigParser) ConfigParser {
+	switch p := parser.(type) {
+	case nil:
+		return nil
+	case *sdkParser:
+		return p.parser
+	}
+	return &coreParser{parser: parser}
 }
@@ -16,11 +16,14 @@ package degradation
 
 import (
 	"context"
+	"encoding/json"
+	"strings"
 	"sync"
 	"sync/atomic"
 
 	"github.com/bytedance/gopkg/lang/fastrand"
 	"github.com/cloudwego/kitex/pkg/acl"
+	"github.com/cloudwego/kitex/pkg/rpcinfo"
 	"github.com/pkg/errors"
 )
 
@@ -31,6 +34,73 @@ var errorDegradation = errors.New("rejected by client degradation config")
 type Config struct {
 	Enable     bool `json:"enable"`
 	Percentage int  `json:"percentage"`
+	// Rules are evaluated in order, the first matching one overrides the percentage.
+	Rules []*Rule `json:"rules,omitempty"`
+	// Error the rejection error, errorDegradation by default.
+	Error *ErrorConfig `json:"error,omitempty"`
+	// Schedule the period the degradation applies in, always if nil.
+	Schedule *Schedule `json:"schedule,omitempty"`
+}
+
+// Wildcard the key of the default config for the methods without one.
+const Wildcard = "*"
+
+// Configs the degradation configs by method, "*" is the default of the methods without one.
+// The legacy payload of a single config is decoded as the default of all the methods.
+type Configs map[string]*Config
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (c *Configs) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw != nil && isLegacyConfig(raw) {
+		config := &Config{}
+		if err := json.Unmarshal(data, config); err != nil {
+			return err
+		}
+		*c = Configs{Wildcard: config}
+		return nil
+	}
+	configs := make(map[string]*Config, len(raw))
+	if err := json.Unmarshal(data, &configs); err != nil {
+		return err
+	}
+	*c = configs
+	return nil
+}
+
+// configFields the json keys of Config, which tell the legacy payload from the method names.
+var configFields = map[string]bool{"enable": true, "percentage": true, "rules": true, "error": true, "schedule": true}
+
+// isLegacyConfig reports whether the payload is a single config, whose keys are the fields of Config.
+func isLegacyConfig(raw map[string]json.RawMessage) bool {
+	for k := range raw {
+		// the json field names are case-insensitive
+		if configFields[strings.ToLower(k)] {
+			return true
+		}
+	}
+	return false
+}
+
+// Get returns the config of the method, or the default one.
+func (c Configs) Get(method string) *Config {
+	if config, ok := c[method]; ok {
+		return config
+	}
+	return c[Wildcard]
+}
+
+// policies the compiled configs by method.
+type policies map[string]*policy
+
+func (p policies) get(method string) *policy {
+	if policy, ok := p[method]; ok {
+		return policy
+	}
+	return p[Wildcard]
 }
 
 type Container struct {
@@ -47,22 +117,38 @@ func GetDefaultDegradationConfig() *Config {
 
 func NewDegradationContainer() *Container {
 	degradationContainer := &Container{}
-	degradationContainer.config.Store(GetDefaultDegradationConfig())
+	degradationContainer.NotifyPolicyChange(GetDefaultDegradationConfig())
 	return degradationContainer
 }
 
+// NotifyPolicyChange applies the config to all the methods.
 func (s *Container) NotifyPolicyChange(cfg *Config) {
-	s.config.Store(cfg)
+	s.NotifyPoliciesChange(Configs{Wildcard: cfg})
+}
+
+// NotifyPoliciesChange applies the configs by method.
+func (s *Container) NotifyPoliciesChange(configs Configs) {
+	compiled := make(policies, len(configs))
+	for method, config := range configs {
+		compiled[method] = compile(config)
+	}
+	s.config.Store(compiled)
 }
 
 func (s *Container) GetACLRule() acl.RejectFunc {
 	return func(ctx context.Context, request interface{}) (reason error) {
-		config := s.config.Load().(*Config)
-		if !config.Enable {
+		compiled := s.config.Load().(policies)
+		ri := rpcinfo.GetRPCInfo(ctx)
+		var method string
+		if ri != nil && ri.To() != nil {
+			method = ri.To().Method()
+		}
+		policy := compiled.get(method)
+		if policy == nil || !policy.config.Enable {
 			return nil
 		}
-		if fastrand.Intn(100) < config.Percentage {
-			return errorDegradation
+		if fastrand.Intn(100) < policy.percentage(ctx, ri) {
+			return policy.reject()
 		}
 		return nil
 	}
@@ -73,10 +159,19 @@ func (c *Config) DeepCopy() *Config {
 	if c == nil {
 		return nil
 	}
-	return &Config{
+	config := &Config{
 		Enable:     c.Enable,
 		Percentage: c.Percentage,
+		Error:      c.Error.DeepCopy(),
+		Schedule:   c.Schedule.DeepCopy(),
+	}
+	if c.Rules != nil {
+		config.Rules = make([]*Rule, len(c.Rules))
+		for i, r := range c.Rules {
+			config.Rules[i] = r.DeepCopy()
+		}
 	}
+	return config
 }
 
 func (c *Config) Equals(other *Config) bool {
@@ -86,5 +181,14 @@ func (c *Config) Equals(other *Config) bool {
 	if c == nil || other == nil {
 		return false
 	}
-	return c.Enable == other.Enable && c.Percentage == other.Percentage
+	if c.Enable != other.Enable || c.Percentage != other.Percentage ||
+		!c.Error.Equals(other.Error) || !c.Schedule.Equals(other.Schedule) || len(c.Rules) != len(other.Rules) {
+		return false
+	}
+	for i := range c.Rules {
+		if !c.Rules[i].Equals(other.Rules[i]) {
+			return false
+		}
+	}
+	return true
 }
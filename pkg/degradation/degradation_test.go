@@ -16,10 +16,12 @@ package degradation
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"testing"
 
 	"github.com/cloudwego/kitex/pkg/acl"
+	"github.com/cloudwego/kitex/pkg/rpcinfo"
 	"github.com/cloudwego/thriftgo/pkg/test"
 )
 
@@ -38,3 +40,41 @@ func TestNewContainer(t *testing.T) {
 	container.NotifyPolicyChange(&Config{Enable: true, Percentage: 100})
 	test.Assert(t, errors.Is(aclMiddleware(invoke)(context.Background(), nil, nil), errorDegradation))
 }
+
+func TestPerMethodConfigs(t *testing.T) {
+	container := NewDegradationContainer()
+	aclMiddleware := acl.NewACLMiddleware([]acl.RejectFunc{container.GetACLRule()})
+	ctx := func(method string) context.Context {
+		ri := rpcinfo.NewRPCInfo(nil, rpcinfo.NewEndpointInfo("svc", method, nil, nil), nil, nil, nil)
+		return rpcinfo.NewCtxWithRPCInfo(context.Background(), ri)
+	}
+	container.NotifyPoliciesChange(Configs{"Recommend": {Enable: true, Percentage: 100}})
+	test.Assert(t, errors.Is(aclMiddleware(invoke)(ctx("Recommend"), nil, nil), errorDegradation))
+	test.Assert(t, errors.Is(aclMiddleware(invoke)(ctx("Checkout"), nil, nil), errFake))
+
+	container.NotifyPoliciesChange(Configs{
+		Wildcard:   {Enable: true, Percentage: 100},
+		"Checkout": {Enable: false},
+	})
+	test.Assert(t, errors.Is(aclMiddleware(invoke)(ctx("Recommend"), nil, nil), errorDegradation))
+	test.Assert(t, errors.Is(aclMiddleware(invoke)(ctx("Checkout"), nil, nil), errFake))
+}
+
+func TestUnmarshalConfigs(t *testing.T) {
+	var configs Configs
+	test.Assert(t, json.Unmarshal([]byte(`{"enable": true, "percentage": 30}`), &configs) == nil)
+	test.Assert(t, configs.Get("echo").Equals(&Config{Enable: true, Percentage: 30}))
+
+	// the legacy configs with the object fields only
+	configs = nil
+	test.Assert(t, json.Unmarshal([]byte(`{"Schedule": {"start": "2024-01-01T00:00:00Z"}, "error": {"kind": "biz", "code": 429}}`), &configs) == nil)
+	test.Assert(t, len(configs) == 1)
+	test.Assert(t, configs.Get("echo").Schedule.Start == "2024-01-01T00:00:00Z")
+	test.Assert(t, configs.Get("echo").Error.Code == 429)
+
+	configs = nil
+	test.Assert(t, json.Unmarshal([]byte(`{"*": {"enable": true, "percentage": 30}, "echo": {"enable": false}}`), &configs) == nil)
+	test.Assert(t, len(configs) == 2)
+	test.Assert(t, configs.Get("echo").Equals(&Config{}))
+	test.Assert(t, configs.Get("other").Equals(&Config{Enable: true, Percentage: 30}))
+}
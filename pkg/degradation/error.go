@@ -0,0 +1,110 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package degradation
+
+import (
+	"fmt"
+
+	"github.com/cloudwego/kitex/pkg/kerrors"
+)
+
+// ErrDegraded is the default rejection error, errors.Is reports true for it and the errors of ErrorKindError.
+var ErrDegraded = errorDegradation
+
+// ErrorKind the kind of the rejection error.
+type ErrorKind string
+
+const (
+	// ErrorKindBizStatus rejects with the Kitex biz status error of the code and message.
+	ErrorKindBizStatus ErrorKind = "biz_status"
+	// ErrorKindError rejects with the *Error of the code and message.
+	ErrorKindError ErrorKind = "error"
+)
+
+// ErrorConfig the error returned when the request is rejected.
+// DON'T FORGET to update DeepCopy() and Equals() if you add new fields.
+type ErrorConfig struct {
+	Kind    ErrorKind `json:"kind"`
+	Code    int32     `json:"code,omitempty"`
+	Message string    `json:"message,omitempty"`
+}
+
+// Error the rejection error of ErrorKindError, which is ErrDegraded.
+type Error struct {
+	Code    int32
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s, code=%d", e.Message, e.Code)
+}
+
+// Is reports whether the target is ErrDegraded.
+func (e *Error) Is(target error) bool {
+	return target == ErrDegraded
+}
+
+// Validate checks the kind and the code of the error config.
+func (c *ErrorConfig) Validate() error {
+	switch c.Kind {
+	case ErrorKindBizStatus:
+		if c.Code == 0 {
+			return fmt.Errorf("code of the %s error must not be 0", c.Kind)
+		}
+	case ErrorKindError:
+	default:
+		return fmt.Errorf("unknown error kind %q", c.Kind)
+	}
+	return nil
+}
+
+// newRejectFunc returns the func creating the rejection error, the biz status error is created per request as it is mutable.
+func newRejectFunc(c *ErrorConfig) func() error {
+	if c == nil {
+		return func() error { return errorDegradation }
+	}
+	message := c.Message
+	if message == "" {
+		message = errorDegradation.Error()
+	}
+	switch c.Kind {
+	case ErrorKindBizStatus:
+		code := c.Code
+		return func() error { return kerrors.NewBizStatusError(code, message) }
+	case ErrorKindError:
+		err := &Error{Code: c.Code, Message: message}
+		return func() error { return err }
+	default:
+		return func() error { return errorDegradation }
+	}
+}
+
+// DeepCopy returns a full copy of ErrorConfig.
+func (c *ErrorConfig) DeepCopy() *ErrorConfig {
+	if c == nil {
+		return nil
+	}
+	return &ErrorConfig{Kind: c.Kind, Code: c.Code, Message: c.Message}
+}
+
+func (c *ErrorConfig) Equals(other *ErrorConfig) bool {
+	if c == nil && other == nil {
+		return true
+	}
+	if c == nil || other == nil {
+		return false
+	}
+	return *c == *other
+}
@@ -0,0 +1,59 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package degradation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cloudwego/kitex/pkg/acl"
+	"github.com/cloudwego/kitex/pkg/kerrors"
+	"github.com/cloudwego/thriftgo/pkg/test"
+)
+
+func TestRejectError(t *testing.T) {
+	container := NewDegradationContainer()
+	aclMiddleware := acl.NewACLMiddleware([]acl.RejectFunc{container.GetACLRule()})
+
+	container.NotifyPolicyChange(&Config{Enable: true, Percentage: 100})
+	err := aclMiddleware(invoke)(context.Background(), nil, nil)
+	test.Assert(t, errors.Is(err, ErrDegraded))
+	test.Assert(t, errors.Is(err, kerrors.ErrACL))
+
+	container.NotifyPolicyChange(&Config{Enable: true, Percentage: 100, Error: &ErrorConfig{
+		Kind: ErrorKindBizStatus, Code: 503, Message: "degraded",
+	}})
+	err = aclMiddleware(invoke)(context.Background(), nil, nil)
+	bizErr, ok := kerrors.FromBizStatusError(err)
+	test.Assert(t, ok)
+	test.Assert(t, bizErr.BizStatusCode() == 503 && bizErr.BizMessage() == "degraded", bizErr)
+
+	container.NotifyPolicyChange(&Config{Enable: true, Percentage: 100, Error: &ErrorConfig{
+		Kind: ErrorKindError, Code: 1001,
+	}})
+	err = aclMiddleware(invoke)(context.Background(), nil, nil)
+	test.Assert(t, errors.Is(err, ErrDegraded))
+	var degraded *Error
+	test.Assert(t, errors.As(err, &degraded))
+	test.Assert(t, degraded.Code == 1001 && degraded.Message == errorDegradation.Error(), degraded)
+}
+
+func TestErrorConfigValidate(t *testing.T) {
+	test.Assert(t, (&ErrorConfig{Kind: ErrorKindBizStatus, Code: 503}).Validate() == nil)
+	test.Assert(t, (&ErrorConfig{Kind: ErrorKindBizStatus}).Validate() != nil)
+	test.Assert(t, (&ErrorConfig{Kind: ErrorKindError}).Validate() == nil)
+	test.Assert(t, (&ErrorConfig{Kind: "unknown"}).Validate() != nil)
+}
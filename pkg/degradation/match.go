@@ -0,0 +1,210 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package degradation
+
+import (
+	"context"
+	"sort"
+
+	"github.com/bytedance/gopkg/cloud/metainfo"
+	"github.com/cloudwego/kitex/pkg/rpcinfo"
+)
+
+// ClusterTag the tag of the callee cluster.
+const ClusterTag = "cluster"
+
+// Rule degrades the requests matching all its conditions with its percentage.
+// DON'T FORGET to update DeepCopy() and Equals() if you add new fields.
+type Rule struct {
+	Match      Match `json:"match"`
+	Percentage int   `json:"percentage"`
+}
+
+// Match the conditions of a rule, the empty conditions match all the requests.
+type Match struct {
+	// CallerTags the tags of the caller endpoint in the rpcinfo.
+	CallerTags map[string]string `json:"caller_tags,omitempty"`
+	// Metainfo the transient values in the metainfo, including the ones from the upstream.
+	Metainfo map[string]string `json:"metainfo,omitempty"`
+	// PersistentMetainfo the persistent values in the metainfo.
+	PersistentMetainfo map[string]string `json:"persistent_metainfo,omitempty"`
+	// Cluster the cluster tag of the callee endpoint.
+	Cluster string `json:"cluster,omitempty"`
+}
+
+type matcher func(ctx context.Context, ri rpcinfo.RPCInfo) bool
+
+type rule struct {
+	matchers   []matcher
+	percentage int
+}
+
+func (r *rule) match(ctx context.Context, ri rpcinfo.RPCInfo) bool {
+	for _, m := range r.matchers {
+		if !m(ctx, ri) {
+			return false
+		}
+	}
+	return true
+}
+
+// policy the config compiled once per config change.
+type policy struct {
+	config *Config
+	rules  []rule
+	reject func() error
+	// schedule nil if the config is not scheduled.
+	schedule *schedule
+	// invalid the config has an invalid schedule, which never degrades.
+	invalid bool
+}
+
+func compile(config *Config) *policy {
+	if config == nil {
+		return nil
+	}
+	p := &policy{config: config, rules: make([]rule, 0, len(config.Rules)), reject: newRejectFunc(config.Error)}
+	for _, r := range config.Rules {
+		if r == nil {
+			continue
+		}
+		p.rules = append(p.rules, rule{matchers: compileMatch(&r.Match), percentage: r.Percentage})
+	}
+	if config.Schedule != nil {
+		s, err := config.Schedule.compile()
+		p.schedule, p.invalid = s, err != nil
+	}
+	return p
+}
+
+func compileMatch(m *Match) []matcher {
+	var matchers []matcher
+	for _, k := range sortedKeys(m.CallerTags) {
+		k, v := k, m.CallerTags[k]
+		matchers = append(matchers, func(ctx context.Context, ri rpcinfo.RPCInfo) bool {
+			if ri == nil || ri.From() == nil {
+				return false
+			}
+			tag, ok := ri.From().Tag(k)
+			return ok && tag == v
+		})
+	}
+	for _, k := range sortedKeys(m.Metainfo) {
+		k, v := k, m.Metainfo[k]
+		matchers = append(matchers, func(ctx context.Context, ri rpcinfo.RPCInfo) bool {
+			value, ok := metainfo.GetValue(ctx, k)
+			return ok && value == v
+		})
+	}
+	for _, k := range sortedKeys(m.PersistentMetainfo) {
+		k, v := k, m.PersistentMetainfo[k]
+		matchers = append(matchers, func(ctx context.Context, ri rpcinfo.RPCInfo) bool {
+			value, ok := metainfo.GetPersistentValue(ctx, k)
+			return ok && value == v
+		})
+	}
+	if m.Cluster != "" {
+		cluster := m.Cluster
+		matchers = append(matchers, func(ctx context.Context, ri rpcinfo.RPCInfo) bool {
+			if ri == nil || ri.To() == nil {
+				return false
+			}
+			tag, ok := ri.To().Tag(ClusterTag)
+			return ok && tag == cluster
+		})
+	}
+	return matchers
+}
+
+// percentage returns the percentage of the first matching rule, or the one of the config,
+// scaled by the schedule at the call time.
+func (p *policy) percentage(ctx context.Context, ri rpcinfo.RPCInfo) int {
+	if p.invalid {
+		return 0
+	}
+	percentage := p.config.Percentage
+	for i := range p.rules {
+		if p.rules[i].match(ctx, ri) {
+			percentage = p.rules[i].percentage
+			break
+		}
+	}
+	if p.schedule != nil {
+		return int(float64(percentage) * p.schedule.factor(now()))
+	}
+	return percentage
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// DeepCopy returns a full copy of Rule.
+func (r *Rule) DeepCopy() *Rule {
+	if r == nil {
+		return nil
+	}
+	return &Rule{
+		Match: Match{
+			CallerTags:         copyMap(r.Match.CallerTags),
+			Metainfo:           copyMap(r.Match.Metainfo),
+			PersistentMetainfo: copyMap(r.Match.PersistentMetainfo),
+			Cluster:            r.Match.Cluster,
+		},
+		Percentage: r.Percentage,
+	}
+}
+
+func (r *Rule) Equals(other *Rule) bool {
+	if r == nil && other == nil {
+		return true
+	}
+	if r == nil || other == nil {
+		return false
+	}
+	return r.Percentage == other.Percentage &&
+		r.Match.Cluster == other.Match.Cluster &&
+		mapEquals(r.Match.CallerTags, other.Match.CallerTags) &&
+		mapEquals(r.Match.Metainfo, other.Match.Metainfo) &&
+		mapEquals(r.Match.PersistentMetainfo, other.Match.PersistentMetainfo)
+}
+
+func copyMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	c := make(map[string]string, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
+func mapEquals(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if bv, ok := b[k]; !ok || bv != v {
+			return false
+		}
+	}
+	return true
+}
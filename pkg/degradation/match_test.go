@@ -0,0 +1,69 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package degradation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bytedance/gopkg/cloud/metainfo"
+	"github.com/cloudwego/kitex/pkg/acl"
+	"github.com/cloudwego/kitex/pkg/rpcinfo"
+	"github.com/cloudwego/thriftgo/pkg/test"
+)
+
+func TestRules(t *testing.T) {
+	container := NewDegradationContainer()
+	aclMiddleware := acl.NewACLMiddleware([]acl.RejectFunc{container.GetACLRule()})
+	newCtx := func(callerTags, calleeTags map[string]string) context.Context {
+		ri := rpcinfo.NewRPCInfo(rpcinfo.NewEndpointInfo("caller", "", nil, callerTags),
+			rpcinfo.NewEndpointInfo("svc", "Echo", nil, calleeTags), nil, nil, nil)
+		return rpcinfo.NewCtxWithRPCInfo(context.Background(), ri)
+	}
+	container.NotifyPolicyChange(&Config{
+		Enable:     true,
+		Percentage: 0,
+		Rules: []*Rule{
+			{Match: Match{Metainfo: map[string]string{"x-traffic": "batch"}}, Percentage: 100},
+			{Match: Match{PersistentMetainfo: map[string]string{"x-env": "canary"}}, Percentage: 100},
+			{Match: Match{CallerTags: map[string]string{"idc": "hl"}, Cluster: "backup"}, Percentage: 100},
+			{Match: Match{CallerTags: map[string]string{"idc": "hl"}}, Percentage: 0},
+			{Percentage: 100},
+		},
+	})
+	reject := func(ctx context.Context) bool {
+		return errors.Is(aclMiddleware(invoke)(ctx, nil, nil), errorDegradation)
+	}
+	ctx := newCtx(map[string]string{"idc": "hl"}, nil)
+	test.Assert(t, !reject(ctx))
+	test.Assert(t, reject(metainfo.WithValue(ctx, "x-traffic", "batch")))
+	test.Assert(t, !reject(metainfo.WithValue(ctx, "x-traffic", "online")))
+	test.Assert(t, reject(metainfo.WithPersistentValue(ctx, "x-env", "canary")))
+	test.Assert(t, reject(newCtx(map[string]string{"idc": "hl"}, map[string]string{ClusterTag: "backup"})))
+	// the first matching rule wins, the last one matches all the requests
+	test.Assert(t, reject(newCtx(map[string]string{"idc": "lf"}, nil)))
+}
+
+func TestRuleDeepCopy(t *testing.T) {
+	config := &Config{Enable: true, Rules: []*Rule{
+		{Match: Match{Metainfo: map[string]string{"x-traffic": "batch"}, Cluster: "default"}, Percentage: 50},
+	}}
+	copied := config.DeepCopy()
+	test.Assert(t, config.Equals(copied))
+	copied.Rules[0].Match.Metainfo["x-traffic"] = "online"
+	test.Assert(t, !config.Equals(copied))
+	test.Assert(t, config.Rules[0].Match.Metainfo["x-traffic"] == "batch")
+}
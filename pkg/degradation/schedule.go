@@ -0,0 +1,226 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package degradation
+
+import (
+	"fmt"
+	"time"
+)
+
+// timeOfDayLayouts the layouts of the start and end of the daily windows.
+var timeOfDayLayouts = []string{"15:04", "15:04:05"}
+
+// now returns the current time, which is replaced by the tests.
+var now = time.Now
+
+// Schedule limits the degradation to the period between start and end and the daily windows,
+// the percentage ramps up from 0 at the beginning of the period and ramps down to 0 at its end.
+// DON'T FORGET to update DeepCopy() and Equals() if you add new fields.
+type Schedule struct {
+	// Start the RFC3339 time the degradation starts at, unbounded if empty.
+	Start string `json:"start,omitempty"`
+	// End the RFC3339 time the degradation ends at, unbounded if empty.
+	End string `json:"end,omitempty"`
+	// Daily the recurring daily windows, all the day if empty.
+	Daily []*Window `json:"daily,omitempty"`
+	// TimeZone the IANA time zone of the daily windows, UTC if empty.
+	TimeZone string `json:"time_zone,omitempty"`
+	// RampUpMS the duration the percentage ramps up from 0 to the configured one.
+	RampUpMS int64 `json:"ramp_up_ms,omitempty"`
+	// RampDownMS the duration the percentage ramps down from the configured one to 0.
+	RampDownMS int64 `json:"ramp_down_ms,omitempty"`
+}
+
+// Window the daily window between the times of day like "20:00", which ends on the next day if end is not after start.
+type Window struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// schedule the compiled Schedule.
+type schedule struct {
+	start, end time.Time
+	loc        *time.Location
+	windows    []window
+	rampUp     time.Duration
+	rampDown   time.Duration
+}
+
+// window the offsets from the midnight.
+type window struct {
+	start, end time.Duration
+}
+
+// Validate checks the times, the time zone and the ramp durations of the schedule.
+func (s *Schedule) Validate() error {
+	_, err := s.compile()
+	return err
+}
+
+func (s *Schedule) compile() (*schedule, error) {
+	c := &schedule{
+		loc:      time.UTC,
+		rampUp:   time.Duration(s.RampUpMS) * time.Millisecond,
+		rampDown: time.Duration(s.RampDownMS) * time.Millisecond,
+	}
+	var err error
+	if s.Start != "" {
+		if c.start, err = time.Parse(time.RFC3339, s.Start); err != nil {
+			return nil, fmt.Errorf("invalid start %q: %w", s.Start, err)
+		}
+	}
+	if s.End != "" {
+		if c.end, err = time.Parse(time.RFC3339, s.End); err != nil {
+			return nil, fmt.Errorf("invalid end %q: %w", s.End, err)
+		}
+	}
+	if !c.start.IsZero() && !c.end.IsZero() && !c.start.Before(c.end) {
+		return nil, fmt.Errorf("start %s must be before end %s", s.Start, s.End)
+	}
+	if s.TimeZone != "" {
+		if c.loc, err = time.LoadLocation(s.TimeZone); err != nil {
+			return nil, fmt.Errorf("invalid time zone %q: %w", s.TimeZone, err)
+		}
+	}
+	for i, w := range s.Daily {
+		if w == nil {
+			return nil, fmt.Errorf("daily window %d must not be empty", i)
+		}
+		start, err := parseTimeOfDay(w.Start)
+		if err != nil {
+			return nil, fmt.Errorf("invalid start %q of daily window %d: %w", w.Start, i, err)
+		}
+		end, err := parseTimeOfDay(w.End)
+		if err != nil {
+			return nil, fmt.Errorf("invalid end %q of daily window %d: %w", w.End, i, err)
+		}
+		if end <= start {
+			end += 24 * time.Hour
+		}
+		c.windows = append(c.windows, window{start: start, end: end})
+	}
+	if c.rampUp < 0 || c.rampDown < 0 {
+		return nil, fmt.Errorf("ramp durations %dms and %dms must not be negative", s.RampUpMS, s.RampDownMS)
+	}
+	// the ramps are anchored to the beginning and the end of the period, which must be set
+	if c.rampUp > 0 && c.start.IsZero() && len(c.windows) == 0 {
+		return nil, fmt.Errorf("ramp up %dms requires start or daily windows", s.RampUpMS)
+	}
+	if c.rampDown > 0 && c.end.IsZero() && len(c.windows) == 0 {
+		return nil, fmt.Errorf("ramp down %dms requires end or daily windows", s.RampDownMS)
+	}
+	return c, nil
+}
+
+func parseTimeOfDay(s string) (time.Duration, error) {
+	var err error
+	for _, layout := range timeOfDayLayouts {
+		var t time.Time
+		if t, err = time.Parse(layout, s); err == nil {
+			return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute +
+				time.Duration(t.Second())*time.Second, nil
+		}
+	}
+	return 0, err
+}
+
+// factor returns the ratio in [0, 1] of the configured percentage at the time,
+// which is 0 out of the schedule and ramps linearly at the beginning and the end of the period.
+func (s *schedule) factor(t time.Time) float64 {
+	if !s.start.IsZero() && t.Before(s.start) || !s.end.IsZero() && !t.Before(s.end) {
+		return 0
+	}
+	start, end := s.start, s.end
+	if len(s.windows) > 0 {
+		ws, we, ok := s.window(t)
+		if !ok {
+			return 0
+		}
+		if start.IsZero() || ws.After(start) {
+			start = ws
+		}
+		if end.IsZero() || we.Before(end) {
+			end = we
+		}
+	}
+	f := 1.0
+	if s.rampUp > 0 && !start.IsZero() {
+		f = min(f, float64(t.Sub(start))/float64(s.rampUp))
+	}
+	if s.rampDown > 0 && !end.IsZero() {
+		f = min(f, float64(end.Sub(t))/float64(s.rampDown))
+	}
+	return max(f, 0)
+}
+
+// window returns the daily window containing the time, the ones starting yesterday may end today.
+func (s *schedule) window(t time.Time) (start, end time.Time, ok bool) {
+	local := t.In(s.loc)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, s.loc)
+	for _, day := range []int{0, -1} {
+		base := midnight.AddDate(0, 0, day)
+		for _, w := range s.windows {
+			start, end = base.Add(w.start), base.Add(w.end)
+			if !t.Before(start) && t.Before(end) {
+				return start, end, true
+			}
+		}
+	}
+	return time.Time{}, time.Time{}, false
+}
+
+// DeepCopy returns a full copy of Schedule.
+func (s *Schedule) DeepCopy() *Schedule {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	if s.Daily != nil {
+		c.Daily = make([]*Window, len(s.Daily))
+		for i, w := range s.Daily {
+			if w != nil {
+				window := *w
+				c.Daily[i] = &window
+			}
+		}
+	}
+	return &c
+}
+
+func (s *Schedule) Equals(other *Schedule) bool {
+	if s == nil && other == nil {
+		return true
+	}
+	if s == nil || other == nil {
+		return false
+	}
+	if s.Start != other.Start || s.End != other.End || s.TimeZone != other.TimeZone ||
+		s.RampUpMS != other.RampUpMS || s.RampDownMS != other.RampDownMS || len(s.Daily) != len(other.Daily) {
+		return false
+	}
+	for i := range s.Daily {
+		a, b := s.Daily[i], other.Daily[i]
+		if a == nil || b == nil {
+			if a != b {
+				return false
+			}
+			continue
+		}
+		if *a != *b {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,95 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package degradation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/thriftgo/pkg/test"
+)
+
+func mockNow(t *testing.T, at time.Time) {
+	now = func() time.Time { return at }
+	t.Cleanup(func() { now = time.Now })
+}
+
+func TestScheduleStartEnd(t *testing.T) {
+	s := &Schedule{Start: "2024-11-11T00:00:00+08:00", End: "2024-11-11T02:00:00+08:00", RampUpMS: 600000, RampDownMS: 600000}
+	test.Assert(t, s.Validate() == nil)
+	p := compile(&Config{Enable: true, Percentage: 60, Schedule: s})
+	start, _ := time.Parse(time.RFC3339, s.Start)
+	for _, c := range []struct {
+		at         time.Duration
+		percentage int
+	}{
+		{-time.Minute, 0},
+		{0, 0},
+		{5 * time.Minute, 30},
+		{10 * time.Minute, 60},
+		{time.Hour, 60},
+		{115 * time.Minute, 30},
+		{2 * time.Hour, 0},
+	} {
+		mockNow(t, start.Add(c.at))
+		test.Assert(t, p.percentage(context.Background(), nil) == c.percentage, c.at, p.percentage(context.Background(), nil))
+	}
+}
+
+func TestScheduleDaily(t *testing.T) {
+	s := &Schedule{Daily: []*Window{{Start: "23:00", End: "01:00"}}, TimeZone: "Asia/Shanghai", RampUpMS: 3600000}
+	test.Assert(t, s.Validate() == nil)
+	p := compile(&Config{Enable: true, Percentage: 100, Schedule: s})
+	loc, _ := time.LoadLocation("Asia/Shanghai")
+	for _, c := range []struct {
+		at         time.Time
+		percentage int
+	}{
+		{time.Date(2024, 11, 10, 22, 59, 0, 0, loc), 0},
+		{time.Date(2024, 11, 10, 23, 30, 0, 0, loc), 50},
+		// the window started yesterday
+		{time.Date(2024, 11, 11, 0, 30, 0, 0, loc), 100},
+		{time.Date(2024, 11, 11, 1, 0, 0, 0, loc), 0},
+		{time.Date(2024, 11, 10, 15, 30, 0, 0, time.UTC), 50},
+	} {
+		mockNow(t, c.at)
+		test.Assert(t, p.percentage(context.Background(), nil) == c.percentage, c.at, p.percentage(context.Background(), nil))
+	}
+}
+
+func TestScheduleInvalid(t *testing.T) {
+	test.Assert(t, (&Schedule{Start: "tomorrow"}).Validate() != nil)
+	test.Assert(t, (&Schedule{TimeZone: "Mars/Base"}).Validate() != nil)
+	test.Assert(t, (&Schedule{Daily: []*Window{{Start: "25:00", End: "01:00"}}}).Validate() != nil)
+	test.Assert(t, (&Schedule{RampUpMS: -1}).Validate() != nil)
+	// the ramps without the period to anchor
+	test.Assert(t, (&Schedule{RampUpMS: 600000}).Validate() != nil)
+	test.Assert(t, (&Schedule{End: "2024-11-11T02:00:00+08:00", RampUpMS: 600000}).Validate() != nil)
+	test.Assert(t, (&Schedule{Start: "2024-11-11T00:00:00+08:00", RampDownMS: 600000}).Validate() != nil)
+	test.Assert(t, (&Schedule{Start: "2024-11-11T00:00:00+08:00", RampUpMS: 600000}).Validate() == nil)
+	test.Assert(t, (&Schedule{Daily: []*Window{{Start: "20:00", End: "22:00"}}, RampDownMS: 600000}).Validate() == nil)
+	// the invalid schedule never degrades
+	p := compile(&Config{Enable: true, Percentage: 100, Schedule: &Schedule{Start: "tomorrow"}})
+	test.Assert(t, p.percentage(context.Background(), nil) == 0)
+}
+
+func TestScheduleDeepCopy(t *testing.T) {
+	config := &Config{Enable: true, Schedule: &Schedule{Daily: []*Window{{Start: "20:00", End: "22:00"}}}}
+	copied := config.DeepCopy()
+	test.Assert(t, config.Equals(copied))
+	copied.Schedule.Daily[0].End = "23:00"
+	test.Assert(t, !config.Equals(copied))
+}
@@ -0,0 +1,385 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package retry extends the kitex retry policy with the conditions to retry on the results, which
+// are configured from nacos and built into retry.ShouldResultRetry.
+package retry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/cloudwego/kitex/pkg/kerrors"
+	"github.com/cloudwego/kitex/pkg/retry"
+	"github.com/cloudwego/kitex/pkg/rpcinfo"
+)
+
+// ContainerKey the reserved key of the retry config for the limits of the retry container.
+const ContainerKey = "$container"
+
+// keep consistent with the limit of the kitex retry circuit breaker.
+const maxRetryPercentage = 30
+
+// Config the payload of the retry category, the policies by method and the container limits under
+// the reserved key "$container".
+type Config struct {
+	Container *Container
+	Policies  map[string]*Policy
+}
+
+// Container the limits of the retry container, which protect the callee from the retry amplification.
+type Container struct {
+	// Disable turns off the retries of all the methods, e.g. during an incident.
+	Disable bool `json:"disable,omitempty"`
+	// MaxRetryPercentage caps the percentage of the retry requests of every method, in (0, 30].
+	MaxRetryPercentage float64 `json:"max_retry_percentage,omitempty"`
+	// CircuitBreaker stops the retries of a method while its error rate is too high.
+	CircuitBreaker *CircuitBreaker `json:"circuit_breaker,omitempty"`
+}
+
+// CircuitBreaker the thresholds of the retry circuit breaker, the retries of a method stop once
+// there are MinSample calls at least and ErrRate of them fail in the recent 10 seconds.
+type CircuitBreaker struct {
+	ErrRate   float64 `json:"err_rate"`
+	MinSample int64   `json:"min_sample"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (c *Config) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	c.Container, c.Policies = nil, make(map[string]*Policy, len(raw))
+	for key, value := range raw {
+		if key == ContainerKey {
+			if err := json.Unmarshal(value, &c.Container); err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+			continue
+		}
+		var p *Policy
+		if err := json.Unmarshal(value, &p); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		c.Policies[key] = p
+	}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (c Config) MarshalJSON() ([]byte, error) {
+	m := make(map[string]interface{}, len(c.Policies)+1)
+	for key, p := range c.Policies {
+		m[key] = p
+	}
+	if c.Container != nil {
+		m[ContainerKey] = c.Container
+	}
+	return json.Marshal(m)
+}
+
+// Validate checks the ranges of the limits.
+func (c *Container) Validate() error {
+	if c.MaxRetryPercentage < 0 || c.MaxRetryPercentage > maxRetryPercentage {
+		return fmt.Errorf("max_retry_percentage %v out of range [0, %d]", c.MaxRetryPercentage, maxRetryPercentage)
+	}
+	if cb := c.CircuitBreaker; cb != nil {
+		if cb.ErrRate <= 0 || cb.ErrRate > 1 {
+			return fmt.Errorf("err_rate %v of circuit_breaker out of range (0, 1]", cb.ErrRate)
+		}
+		if cb.MinSample <= 0 {
+			return fmt.Errorf("min_sample %d of circuit_breaker must be positive", cb.MinSample)
+		}
+	}
+	return nil
+}
+
+// Policy the retry policy of a method in the retry category.
+type Policy struct {
+	retry.Policy
+	ResultRetry *ResultRetry `json:"result_retry,omitempty"`
+}
+
+// ResultRetry the results to retry on besides the timeouts, it works with the failure and mixed policies only.
+type ResultRetry struct {
+	// ErrorTypes the kitex error types, such as "remote_or_network" and "get_connection".
+	ErrorTypes []string `json:"error_types,omitempty"`
+	// BizStatusCodes the biz status codes of the responses or the errors.
+	BizStatusCodes []int32 `json:"biz_status_codes,omitempty"`
+	// RespFields the predicates on the fields of the response, a response matching any of them is retried.
+	RespFields []FieldPredicate `json:"resp_fields,omitempty"`
+	// NotRetryForTimeout disables the default retry on timeout, e.g. for the non-idempotent methods.
+	NotRetryForTimeout bool `json:"not_retry_for_timeout,omitempty"`
+}
+
+// FieldPredicate compares a field of the response with the value.
+type FieldPredicate struct {
+	// Field the path of the field by the field names or the json tags, e.g. "BaseResp.StatusCode".
+	Field string `json:"field"`
+	// Op one of eq (default), ne, gt, gte, lt, lte and in, the value of in is a list.
+	Op    string      `json:"op,omitempty"`
+	Value interface{} `json:"value"`
+}
+
+// the kitex error types by name.
+var errorTypes = map[string]error{
+	"internal_exception": kerrors.ErrInternalException,
+	"service_discovery":  kerrors.ErrServiceDiscovery,
+	"get_connection":     kerrors.ErrGetConnection,
+	"loadbalance":        kerrors.ErrLoadbalance,
+	"no_more_instance":   kerrors.ErrNoMoreInstance,
+	"rpc_timeout":        kerrors.ErrRPCTimeout,
+	"remote_or_network":  kerrors.ErrRemoteOrNetwork,
+	"overlimit":          kerrors.ErrOverlimit,
+	"panic":              kerrors.ErrPanic,
+	"biz":                kerrors.ErrBiz,
+	"route":              kerrors.ErrRoute,
+}
+
+// the supported ops, true for the ordering ones which only accept the numbers.
+var ops = map[string]bool{"eq": false, "ne": false, "gt": true, "gte": true, "lt": true, "lte": true, "in": false}
+
+// Validate checks the error types and the predicates.
+func (r *ResultRetry) Validate() error {
+	for _, t := range r.ErrorTypes {
+		if _, ok := errorTypes[t]; !ok {
+			return fmt.Errorf("unknown error type %q", t)
+		}
+	}
+	for _, p := range r.RespFields {
+		if p.Field == "" {
+			return errors.New("field of resp_fields must not be empty")
+		}
+		op := p.op()
+		ordering, ok := ops[op]
+		if !ok {
+			return fmt.Errorf("unsupported op %q of field %s", p.Op, p.Field)
+		}
+		if _, ok := number(p.Value); ordering && !ok {
+			return fmt.Errorf("the value of field %s must be a number for op %s", p.Field, op)
+		}
+		if _, ok := p.Value.([]interface{}); ok != (op == "in") {
+			return fmt.Errorf("the value of field %s must be a list if and only if the op is in", p.Field)
+		}
+	}
+	return nil
+}
+
+// ShouldResultRetry builds the kitex ShouldResultRetry.
+func (r *ResultRetry) ShouldResultRetry() (*retry.ShouldResultRetry, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	rr := &retry.ShouldResultRetry{NotRetryForTimeout: r.NotRetryForTimeout}
+	codes := make(map[int32]bool, len(r.BizStatusCodes))
+	for _, code := range r.BizStatusCodes {
+		codes[code] = true
+	}
+	if len(r.ErrorTypes) > 0 || len(codes) > 0 {
+		targets := make([]error, 0, len(r.ErrorTypes))
+		for _, t := range r.ErrorTypes {
+			targets = append(targets, errorTypes[t])
+		}
+		rr.ErrorRetryWithCtx = func(ctx context.Context, err error, ri rpcinfo.RPCInfo) bool {
+			for _, target := range targets {
+				if errors.Is(err, target) {
+					return true
+				}
+			}
+			be, ok := kerrors.FromBizStatusError(err)
+			return ok && codes[be.BizStatusCode()]
+		}
+	}
+	if len(codes) > 0 || len(r.RespFields) > 0 {
+		predicates := r.RespFields
+		rr.RespRetryWithCtx = func(ctx context.Context, resp interface{}, ri rpcinfo.RPCInfo) bool {
+			if ri != nil && ri.Invocation() != nil {
+				if be := ri.Invocation().BizStatusErr(); be != nil && codes[be.BizStatusCode()] {
+					return true
+				}
+			}
+			for _, p := range predicates {
+				if p.match(resp) {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	return rr, nil
+}
+
+func (p *FieldPredicate) op() string {
+	if p.Op == "" {
+		return "eq"
+	}
+	return p.Op
+}
+
+func (p *FieldPredicate) match(resp interface{}) bool {
+	// the kitex results wrap the responses
+	if r, ok := resp.(interface{ GetResult() interface{} }); ok {
+		resp = r.GetResult()
+	}
+	v, ok := lookup(reflect.ValueOf(resp), strings.Split(p.Field, "."))
+	if !ok {
+		return false
+	}
+	field := scalar(v)
+	if p.op() == "in" {
+		for _, value := range p.Value.([]interface{}) {
+			if c, ok := compare(field, value); ok && c == 0 {
+				return true
+			}
+		}
+		return false
+	}
+	// the values of different types never match, even for ne
+	c, ok := compare(field, p.Value)
+	if !ok {
+		return false
+	}
+	switch p.op() {
+	case "eq":
+		return c == 0
+	case "ne":
+		return c != 0
+	case "gt":
+		return c > 0
+	case "gte":
+		return c >= 0
+	case "lt":
+		return c < 0
+	case "lte":
+		return c <= 0
+	}
+	return false
+}
+
+// lookup walks the path through the structs by the field names or json tags, and the maps with string keys.
+func lookup(v reflect.Value, path []string) (reflect.Value, bool) {
+	for _, name := range path {
+		v = indirect(v)
+		switch v.Kind() {
+		case reflect.Struct:
+			f, ok := structField(v, name)
+			if !ok {
+				return reflect.Value{}, false
+			}
+			v = f
+		case reflect.Map:
+			if v.Type().Key().Kind() != reflect.String {
+				return reflect.Value{}, false
+			}
+			v = v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+			if !v.IsValid() {
+				return reflect.Value{}, false
+			}
+		default:
+			return reflect.Value{}, false
+		}
+	}
+	v = indirect(v)
+	return v, v.IsValid()
+}
+
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+func structField(v reflect.Value, name string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		if f.Name == name || strings.Split(f.Tag.Get("json"), ",")[0] == name {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// scalar converts the numbers to float64 as the values decoded from the config.
+func scalar(v reflect.Value) interface{} {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return v.Bool()
+	}
+	return nil
+}
+
+// compare returns the order of the field and the value, false if they are not comparable.
+func compare(field, value interface{}) (int, bool) {
+	switch f := field.(type) {
+	case float64:
+		n, ok := number(value)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case f < n:
+			return -1, true
+		case f > n:
+			return 1, true
+		}
+		return 0, true
+	case string:
+		s, ok := value.(string)
+		if !ok {
+			return 0, false
+		}
+		return strings.Compare(f, s), true
+	case bool:
+		b, ok := value.(bool)
+		if !ok || b != f {
+			return 1, ok
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// number converts the number decoded from the config to float64.
+func number(value interface{}) (float64, bool) {
+	switch x := value.(type) {
+	case float64:
+		return x, true
+	case int:
+		return float64(x), true
+	case int64:
+		return float64(x), true
+	}
+	return 0, false
+}
@@ -0,0 +1,127 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retry
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/cloudwego/kitex/pkg/kerrors"
+	"github.com/cloudwego/kitex/pkg/rpcinfo"
+	"github.com/stretchr/testify/assert"
+)
+
+type baseResp struct {
+	StatusCode int32  `json:"status_code"`
+	Message    string `json:"message"`
+}
+
+type echoResponse struct {
+	BaseResp *baseResp
+}
+
+type echoResult struct {
+	Success *echoResponse
+}
+
+func (r *echoResult) GetResult() interface{} {
+	return r.Success
+}
+
+func TestShouldResultRetry(t *testing.T) {
+	r := &ResultRetry{
+		ErrorTypes:     []string{"remote_or_network"},
+		BizStatusCodes: []int32{503},
+		RespFields: []FieldPredicate{
+			{Field: "BaseResp.status_code", Op: "in", Value: []interface{}{502.0, 504.0}},
+			{Field: "BaseResp.Message", Value: "busy"},
+		},
+		NotRetryForTimeout: true,
+	}
+	rr, err := r.ShouldResultRetry()
+	assert.Nil(t, err)
+	assert.True(t, rr.NotRetryForTimeout)
+
+	ctx := context.Background()
+	assert.True(t, rr.ErrorRetryWithCtx(ctx, kerrors.ErrRemoteOrNetwork.WithCause(context.Canceled), nil))
+	assert.True(t, rr.ErrorRetryWithCtx(ctx, kerrors.NewBizStatusError(503, "unavailable"), nil))
+	assert.False(t, rr.ErrorRetryWithCtx(ctx, kerrors.ErrGetConnection, nil))
+
+	resp := func(code int32, msg string) interface{} {
+		return &echoResult{Success: &echoResponse{BaseResp: &baseResp{StatusCode: code, Message: msg}}}
+	}
+	assert.True(t, rr.RespRetryWithCtx(ctx, resp(502, ""), nil))
+	assert.True(t, rr.RespRetryWithCtx(ctx, resp(0, "busy"), nil))
+	assert.False(t, rr.RespRetryWithCtx(ctx, resp(500, ""), nil))
+	assert.False(t, rr.RespRetryWithCtx(ctx, &echoResult{}, nil))
+
+	ink := rpcinfo.NewInvocation("svc", "Echo")
+	ink.SetBizStatusErr(kerrors.NewBizStatusError(503, "unavailable"))
+	ri := rpcinfo.NewRPCInfo(nil, nil, ink, nil, nil)
+	assert.True(t, rr.RespRetryWithCtx(ctx, resp(0, ""), ri))
+}
+
+func TestFieldPredicate(t *testing.T) {
+	resp := map[string]interface{}{"code": 3, "ok": false}
+	for _, c := range []struct {
+		p    FieldPredicate
+		want bool
+	}{
+		{FieldPredicate{Field: "code", Op: "gt", Value: 2.0}, true},
+		{FieldPredicate{Field: "code", Op: "lte", Value: 2.0}, false},
+		{FieldPredicate{Field: "code", Op: "ne", Value: 3.0}, false},
+		{FieldPredicate{Field: "ok", Value: false}, true},
+		{FieldPredicate{Field: "missing", Value: 1.0}, false},
+		// the values of different types never match
+		{FieldPredicate{Field: "code", Op: "ne", Value: "3"}, false},
+		{FieldPredicate{Field: "ok", Op: "ne", Value: 0.0}, false},
+		{FieldPredicate{Field: "code", Op: "gt", Value: true}, false},
+	} {
+		assert.Equal(t, c.want, c.p.match(resp), c.p)
+	}
+
+	_, err := (&ResultRetry{ErrorTypes: []string{"unknown"}}).ShouldResultRetry()
+	assert.NotNil(t, err)
+	for _, value := range []interface{}{"500", true, nil} {
+		_, err = (&ResultRetry{RespFields: []FieldPredicate{{Field: "code", Op: "gte", Value: value}}}).ShouldResultRetry()
+		assert.NotNil(t, err, value)
+	}
+	_, err = (&ResultRetry{RespFields: []FieldPredicate{{Field: "code", Op: "lt", Value: 500}}}).ShouldResultRetry()
+	assert.Nil(t, err)
+}
+
+func TestConfig(t *testing.T) {
+	c := &Config{}
+	err := json.Unmarshal([]byte(`{"$container": {"disable": true, "max_retry_percentage": 10},
+		"Echo": {"enable": true, "type": 0, "failure_policy": {"stop_policy": {"max_retry_times": 2}}}}`), c)
+	assert.Nil(t, err)
+	assert.Equal(t, &Container{Disable: true, MaxRetryPercentage: 10}, c.Container)
+	assert.Equal(t, 2, c.Policies["Echo"].FailurePolicy.StopPolicy.MaxRetryTimes)
+	assert.NotContains(t, c.Policies, ContainerKey)
+
+	buf, err := json.Marshal(c)
+	assert.Nil(t, err)
+	got := &Config{}
+	assert.Nil(t, json.Unmarshal(buf, got))
+	assert.Equal(t, c, got)
+
+	assert.NotNil(t, json.Unmarshal([]byte(`{"$container": {"disable": 1}}`), c))
+
+	assert.Nil(t, (&Container{MaxRetryPercentage: 30, CircuitBreaker: &CircuitBreaker{ErrRate: 0.5, MinSample: 10}}).Validate())
+	assert.NotNil(t, (&Container{MaxRetryPercentage: 31}).Validate())
+	assert.NotNil(t, (&Container{CircuitBreaker: &CircuitBreaker{ErrRate: 0.5}}).Validate())
+	assert.NotNil(t, (&Container{CircuitBreaker: &CircuitBreaker{MinSample: 10}}).Validate())
+}
@@ -0,0 +1,314 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package schema generates the JSON Schemas of the governance configs and validates the payloads against them.
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/cloudwego/kitex/pkg/circuitbreak"
+	"github.com/cloudwego/kitex/pkg/limiter"
+	"github.com/cloudwego/kitex/pkg/retry"
+	"github.com/cloudwego/kitex/pkg/rpctimeout"
+
+	"github.com/kitex-contrib/config-nacos/pkg/degradation"
+	retrypolicy "github.com/kitex-contrib/config-nacos/pkg/retry"
+)
+
+// Draft the JSON Schema draft of the generated schemas.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// the payload shapes of the categories, keep consistent with the category names of the client and server suites.
+var categories = map[string]interface{}{
+	"retry":                  retrypolicy.Config{},
+	"rpc_timeout":            map[string]*rpctimeout.RPCTimeout{},
+	"circuit_break":          map[string]circuitbreak.CBConfig{},
+	"instance_circuit_break": circuitbreak.CBConfig{},
+	"degradation":            degradation.Configs{},
+	"limit":                  limiter.LimiterConfig{},
+}
+
+var (
+	unmarshalerType       = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	retryConfigType       = reflect.TypeOf(retrypolicy.Config{})
+	degradationConfigType = reflect.TypeOf(degradation.Configs{})
+)
+
+// the allowed values of the enum types.
+var enums = map[reflect.Type][]interface{}{
+	reflect.TypeOf(retry.Type(0)): {
+		int(retry.FailureType), int(retry.BackupType), int(retry.MixedType),
+	},
+	reflect.TypeOf(retry.BackOffType("")): {
+		string(retry.NoneBackOffType), string(retry.FixedBackOffType), string(retry.RandomBackOffType),
+	},
+	reflect.TypeOf(degradation.ErrorKind("")): {
+		string(degradation.ErrorKindBizStatus), string(degradation.ErrorKindError),
+	},
+}
+
+// Schema a subset of JSON Schema which is enough to describe the governance configs.
+// The null value is accepted everywhere, the same as the json decoder.
+type Schema struct {
+	Schema     string             `json:"$schema,omitempty"`
+	Title      string             `json:"title,omitempty"`
+	Type       string             `json:"type,omitempty"`
+	Properties map[string]*Schema `json:"properties,omitempty"`
+	// AdditionalProperties false for the structs, or the schema of the map values.
+	AdditionalProperties interface{}   `json:"additionalProperties,omitempty"`
+	Items                *Schema       `json:"items,omitempty"`
+	Enum                 []interface{} `json:"enum,omitempty"`
+	Minimum              *float64      `json:"minimum,omitempty"`
+}
+
+// Categories returns the names of the categories which have schemas.
+func Categories() []string {
+	names := make([]string, 0, len(categories))
+	for name := range categories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// For returns the schema of the category, false if the category is unknown.
+func For(category string) (*Schema, bool) {
+	v, ok := categories[category]
+	if !ok {
+		return nil, false
+	}
+	s := Generate(v)
+	s.Title = category
+	return s, true
+}
+
+// Generate generates the schema of the value by the json tags of its type.
+func Generate(v interface{}) *Schema {
+	s := generate(reflect.TypeOf(v))
+	s.Schema = Draft
+	return s
+}
+
+func generate(t reflect.Type) *Schema {
+	s := generateKind(t)
+	if values, ok := enums[t]; ok {
+		s.Enum = values
+	}
+	return s
+}
+
+func generateKind(t reflect.Type) *Schema {
+	// the retry config is a map of the policies with the reserved key of the container limits
+	if t == retryConfigType {
+		return &Schema{
+			Type: "object",
+			Properties: map[string]*Schema{
+				retrypolicy.ContainerKey: generate(reflect.TypeOf(retrypolicy.Container{})),
+			},
+			AdditionalProperties: generate(reflect.TypeOf(retrypolicy.Policy{})),
+		}
+	}
+	// the degradation configs by method, or a single config of the legacy payload
+	if t == degradationConfigType {
+		s := generate(reflect.TypeOf(degradation.Config{}))
+		s.AdditionalProperties = generate(reflect.TypeOf(degradation.Config{}))
+		return s
+	}
+	// the types decoding themselves accept any value
+	if t.Implements(unmarshalerType) || reflect.PtrTo(t).Implements(unmarshalerType) {
+		return &Schema{}
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return generate(t.Elem())
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Schema{Type: "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		zero := float64(0)
+		return &Schema{Type: "integer", Minimum: &zero}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: generate(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: generate(t.Elem())}
+	case reflect.Struct:
+		s := &Schema{Type: "object", Properties: map[string]*Schema{}, AdditionalProperties: false}
+		fields(t, s.Properties)
+		return s
+	default:
+		return &Schema{}
+	}
+}
+
+// fields collects the properties of the struct, the fields of the embedded structs are inlined.
+func fields(t reflect.Type, props map[string]*Schema) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		ft := f.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			fields(ft, props)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		props[name] = generate(f.Type)
+	}
+}
+
+// Validate validates the generic json tree against the schema, the error reports the path of the mismatched value.
+func (s *Schema) Validate(doc interface{}) error {
+	return s.validate("", doc)
+}
+
+func (s *Schema) validate(path string, v interface{}) error {
+	if v == nil {
+		return nil
+	}
+	switch s.Type {
+	case "object":
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return mismatch(path, s.Type, v)
+		}
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			child := join(path, k)
+			if p, ok := s.Properties[k]; ok {
+				if err := p.validate(child, m[k]); err != nil {
+					return err
+				}
+				continue
+			}
+			switch ap := s.AdditionalProperties.(type) {
+			case *Schema:
+				if err := ap.validate(child, m[k]); err != nil {
+					return err
+				}
+			case bool:
+				if !ap {
+					return fmt.Errorf("%s: unknown field", child)
+				}
+			}
+		}
+	case "array":
+		items, ok := v.([]interface{})
+		if !ok {
+			return mismatch(path, s.Type, v)
+		}
+		for i, item := range items {
+			if s.Items == nil {
+				break
+			}
+			if err := s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item); err != nil {
+				return err
+			}
+		}
+	case "string":
+		if _, ok := v.(string); !ok {
+			return mismatch(path, s.Type, v)
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return mismatch(path, s.Type, v)
+		}
+	case "number", "integer":
+		n, ok := number(v)
+		if !ok || (s.Type == "integer" && n != math.Trunc(n)) {
+			return mismatch(path, s.Type, v)
+		}
+		if s.Minimum != nil && n < *s.Minimum {
+			return fmt.Errorf("%s: %v is less than the minimum %v", pathOrRoot(path), v, *s.Minimum)
+		}
+	}
+	if len(s.Enum) > 0 && !s.inEnum(v) {
+		return fmt.Errorf("%s: %v is not one of %v", pathOrRoot(path), v, s.Enum)
+	}
+	return nil
+}
+
+func (s *Schema) inEnum(v interface{}) bool {
+	n, isNumber := number(v)
+	for _, e := range s.Enum {
+		if en, ok := number(e); ok && isNumber {
+			if en == n {
+				return true
+			}
+			continue
+		}
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
+
+func number(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func mismatch(path, typ string, v interface{}) error {
+	return fmt.Errorf("%s: expect %s, got %T", pathOrRoot(path), typ, v)
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func pathOrRoot(path string) string {
+	if path == "" {
+		return "$"
+	}
+	return path
+}
@@ -0,0 +1,85 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/yaml"
+)
+
+func TestGenerate(t *testing.T) {
+	assert.Equal(t, []string{"circuit_break", "degradation", "instance_circuit_break", "limit", "retry", "rpc_timeout"}, Categories())
+	_, ok := For("unknown")
+	assert.False(t, ok)
+
+	s, ok := For("retry")
+	assert.True(t, ok)
+	buf, err := json.Marshal(s)
+	assert.Nil(t, err)
+	assert.True(t, strings.Contains(string(buf), `"$schema":"`+Draft+`"`))
+	assert.True(t, strings.Contains(string(buf), `"title":"retry"`))
+
+	policy := s.AdditionalProperties.(*Schema)
+	assert.Equal(t, "object", policy.Type)
+	assert.Equal(t, false, policy.AdditionalProperties)
+	assert.Equal(t, []interface{}{0, 1, 2}, policy.Properties["type"].Enum)
+	// the fields of the embedded FailurePolicy are inlined
+	mixed := policy.Properties["mixed_policy"]
+	assert.NotNil(t, mixed.Properties["retry_delay_ms"])
+	assert.NotNil(t, mixed.Properties["stop_policy"])
+	assert.Nil(t, mixed.Properties["ShouldResultRetry"])
+}
+
+func TestValidate(t *testing.T) {
+	s, _ := For("retry")
+	validate := func(data string) error {
+		var tree interface{}
+		assert.Nil(t, yaml.Unmarshal([]byte(data), &tree))
+		return s.Validate(tree)
+	}
+
+	assert.Nil(t, validate(`
+"*":
+  enable: true
+  type: 0
+  failure_policy:
+    stop_policy:
+      max_retry_times: 3
+      cb_policy:
+        error_rate: 0.1
+    backoff_policy:
+      backoff_type: fixed
+      cfg_items:
+        fix_ms: 50
+`))
+	assert.EqualError(t, validate(`{"*": {"failure_policy": {"stop_policy": {"max_retry_time": 3}}}}`),
+		"*.failure_policy.stop_policy.max_retry_time: unknown field")
+	assert.EqualError(t, validate(`{"m1": {"type": 3}}`), "m1.type: 3 is not one of [0 1 2]")
+	assert.EqualError(t, validate(`{"m1": {"type": 0.5}}`), "m1.type: expect integer, got float64")
+	assert.EqualError(t, validate(`{"m1": {"enable": "true"}}`), "m1.enable: expect boolean, got string")
+	assert.EqualError(t, validate(`{"m1": {"backup_policy": {"retry_delay_ms": -1}}}`),
+		"m1.backup_policy.retry_delay_ms: -1 is less than the minimum 0")
+	assert.EqualError(t, validate(`{"m1": {"failure_policy": {"backoff_policy": {"backoff_type": "linear"}}}}`),
+		"m1.failure_policy.backoff_policy.backoff_type: linear is not one of [none fixed random]")
+	assert.EqualError(t, validate(`[]`), "$: expect object, got []interface {}")
+	assert.Nil(t, validate(`{"m1": null}`))
+
+	s, _ = For("limit")
+	assert.EqualError(t, s.Validate(map[string]interface{}{"qps": float64(1)}), "qps: unknown field")
+}
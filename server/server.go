@@ -0,0 +1,42 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"github.com/cloudwego/kitex/server"
+
+	nacosserver "github.com/kitex-contrib/config-nacos/internal/server"
+	"github.com/kitex-contrib/config-nacos/nacos"
+	"github.com/kitex-contrib/config-nacos/utils"
+)
+
+// The server governance is implemented by the internal package shared with the v2 module.
+
+// NacosServerSuite nacos server config suite, configure limiter config dynamically from nacos.
+type NacosServerSuite = nacosserver.NacosServerSuite
+
+// NewSuite service is the destination service.
+func NewSuite(service string, cli nacos.Client, opts ...utils.Option) *NacosServerSuite {
+	su := utils.Options{}
+	for _, opt := range opts {
+		opt.Apply(&su)
+	}
+	return nacosserver.NewSuite(service, nacos.CoreClient(cli, su.NacosCustomFunctions), su.Options)
+}
+
+// WithLimiter sets the limiter config from nacos configuration center.
+func WithLimiter(dest string, nacosClient nacos.Client, opts utils.Options) server.Option {
+	return nacosserver.WithLimiter(dest, nacos.CoreClient(nacosClient, opts.NacosCustomFunctions), opts.Options)
+}
@@ -0,0 +1,48 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import "github.com/kitex-contrib/config-nacos/internal/utils"
+
+const (
+	DryRunAll = utils.DryRunAll
+	StrictAll = utils.StrictAll
+
+	WildcardMethod     = utils.WildcardMethod
+	RegexpMethodPrefix = utils.RegexpMethodPrefix
+)
+
+type (
+	Validator     = utils.Validator
+	ValidatorFunc = utils.ValidatorFunc
+	Set           = utils.Set
+	ThreadSafeSet = utils.ThreadSafeSet
+	MethodMatcher = utils.MethodMatcher
+)
+
+// NewMethodMatcher compiles the keys of a per-method config.
+func NewMethodMatcher(keys []string) (*MethodMatcher, error) {
+	return utils.NewMethodMatcher(keys)
+}
+
+// IsMethodPattern reports whether the key is a glob or regular expression rather than a method name.
+func IsMethodPattern(key string) bool {
+	return utils.IsMethodPattern(key)
+}
+
+// Diff returns the differences from old to new by json path.
+func Diff(old, new interface{}) []string {
+	return utils.Diff(old, new)
+}
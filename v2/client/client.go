@@ -0,0 +1,57 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"github.com/cloudwego/kitex/client"
+
+	nacosclient "github.com/kitex-contrib/config-nacos/internal/client"
+	"github.com/kitex-contrib/config-nacos/v2/nacos"
+	"github.com/kitex-contrib/config-nacos/v2/utils"
+)
+
+// The client governance is implemented by the internal package shared with the v1 module.
+
+// NacosClientSuite nacos client config suite, configure retry timeout limit and circuitbreak dynamically from nacos.
+type NacosClientSuite = nacosclient.NacosClientSuite
+
+// NewSuite service is the destination service name and client is the local identity.
+func NewSuite(service, client string, cli nacos.Client, opts ...utils.Option) *NacosClientSuite {
+	su := utils.Options{}
+	for _, opt := range opts {
+		opt.Apply(&su)
+	}
+	return nacosclient.NewSuite(service, client, nacos.CoreClient(cli, su.NacosCustomFunctions), su.Options)
+}
+
+// WithRetryPolicy sets the retry policy from nacos configuration center.
+func WithRetryPolicy(dest, src string, nacosClient nacos.Client, opts utils.Options) []client.Option {
+	return nacosclient.WithRetryPolicy(dest, src, nacos.CoreClient(nacosClient, opts.NacosCustomFunctions), opts.Options)
+}
+
+// WithRPCTimeout sets the RPC timeout policy from nacos configuration center.
+func WithRPCTimeout(dest, src string, nacosClient nacos.Client, opts utils.Options) []client.Option {
+	return nacosclient.WithRPCTimeout(dest, src, nacos.CoreClient(nacosClient, opts.NacosCustomFunctions), opts.Options)
+}
+
+// WithCircuitBreaker sets the circuit breaker policy from nacos configuration center.
+func WithCircuitBreaker(dest, src string, nacosClient nacos.Client, opts utils.Options) []client.Option {
+	return nacosclient.WithCircuitBreaker(dest, src, nacos.CoreClient(nacosClient, opts.NacosCustomFunctions), opts.Options)
+}
+
+// WithDegradation sets the degradation policy from nacos configuration center.
+func WithDegradation(dest, src string, nacosClient nacos.Client, opts utils.Options) []client.Option {
+	return nacosclient.WithDegradation(dest, src, nacos.CoreClient(nacosClient, opts.NacosCustomFunctions), opts.Options)
+}
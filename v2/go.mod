@@ -3,15 +3,21 @@ module github.com/kitex-contrib/config-nacos/v2
 go 1.21
 
 require (
-	github.com/bytedance/gopkg v0.1.1
 	github.com/cloudwego/kitex v0.11.3
 	github.com/cloudwego/kitex-examples v0.3.3
-	github.com/cloudwego/thriftgo v0.3.17
+	github.com/kitex-contrib/config-nacos v0.0.0-20261019025409-dcfcd8484d69
 	github.com/nacos-group/nacos-sdk-go/v2 v2.2.5
-	github.com/pkg/errors v0.9.1
 	github.com/stretchr/testify v1.10.0
-	go.uber.org/atomic v1.11.0
-	sigs.k8s.io/yaml v1.4.0
+)
+
+require (
+	github.com/BurntSushi/toml v1.3.2 // indirect
+	github.com/bytedance/gopkg v0.1.1 // indirect
+	github.com/cloudwego/thriftgo v0.3.18 // indirect
+	github.com/pkg/errors v0.9.1 // indirect
+	go.uber.org/atomic v1.11.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+	sigs.k8s.io/yaml v1.4.0 // indirect
 )
 
 require (
@@ -75,7 +81,11 @@ require (
 	google.golang.org/protobuf v1.31.0 // indirect
 	gopkg.in/ini.v1 v1.66.2 // indirect
 	gopkg.in/natefinch/lumberjack.v2 v2.0.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
 
 replace github.com/apache/thrift => github.com/apache/thrift v0.13.0
+
+// The governance core is shared with the root module, the required version is the root commit containing
+// the core and internal packages. The replace keeps both modules in sync for the development in the repo,
+// drop it and require the root tag before releasing this module.
+replace github.com/kitex-contrib/config-nacos => ../
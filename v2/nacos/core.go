@@ -0,0 +1,130 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nacos
+
+import (
+	"errors"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+
+	"github.com/kitex-contrib/config-nacos/core"
+)
+
+// CoreClient adapts the client to the core shared with the v1 module, the custom functions are applied
+// on the rendered config params in order.
+func CoreClient(cli Client, fns []CustomFunction) core.Client {
+	return &coreClient{cli: cli, fns: fns}
+}
+
+var _ core.Client = &coreClient{}
+
+var errUnversioned = errors.New("the nacos client doesn't keep the versions")
+
+type coreClient struct {
+	cli Client
+	fns []CustomFunction
+}
+
+func (c *coreClient) customize(param vo.ConfigParam, err error) (core.ConfigParam, error) {
+	if err != nil {
+		return coreParam(param), err
+	}
+	for _, f := range c.fns {
+		f(&param)
+	}
+	return coreParam(param), nil
+}
+
+// SetParser implements core.Client.
+func (c *coreClient) SetParser(parser core.ConfigParser) {
+	c.cli.SetParser(parser)
+}
+
+// ClientConfigParam implements core.Client.
+func (c *coreClient) ClientConfigParam(cpc *ConfigParamConfig) (core.ConfigParam, error) {
+	return c.customize(c.cli.ClientConfigParam(cpc))
+}
+
+// ServerConfigParam implements core.Client.
+func (c *coreClient) ServerConfigParam(cpc *ConfigParamConfig) (core.ConfigParam, error) {
+	return c.customize(c.cli.ServerConfigParam(cpc))
+}
+
+// RegisterConfigCallback implements core.Client.
+func (c *coreClient) RegisterConfigCallback(param core.ConfigParam, callback func(string, core.ConfigParser), uniqueID int64) {
+	c.cli.RegisterConfigCallback(sdkParam(param), callback, uniqueID)
+}
+
+// DeregisterConfig implements core.Client.
+func (c *coreClient) DeregisterConfig(param core.ConfigParam, uniqueID int64) error {
+	return c.cli.DeregisterConfig(sdkParam(param), uniqueID)
+}
+
+// History implements core.Client, it's empty if the client isn't a VersionedClient.
+func (c *coreClient) History(param core.ConfigParam) []core.Version {
+	if vc, ok := c.cli.(VersionedClient); ok {
+		return vc.History(sdkParam(param))
+	}
+	return nil
+}
+
+// Rollback implements core.Client.
+func (c *coreClient) Rollback(param core.ConfigParam, version int64) error {
+	if vc, ok := c.cli.(VersionedClient); ok {
+		return vc.Rollback(sdkParam(param), version)
+	}
+	return errUnversioned
+}
+
+// Unpin implements core.Client.
+func (c *coreClient) Unpin(param core.ConfigParam) error {
+	if vc, ok := c.cli.(VersionedClient); ok {
+		return vc.Unpin(sdkParam(param))
+	}
+	return errUnversioned
+}
+
+// Status implements core.Client, it's zero if the client isn't a VersionedClient.
+func (c *coreClient) Status(param core.ConfigParam) core.Status {
+	if vc, ok := c.cli.(VersionedClient); ok {
+		return vc.Status(sdkParam(param))
+	}
+	return core.Status{}
+}
+
+// coreParam converts the param to the core, which keeps it as the native one. The DatumId is left
+// empty as it's not supported by the nacos sdk v2.
+func coreParam(param vo.ConfigParam) core.ConfigParam {
+	return core.ConfigParam{
+		DataId:   param.DataId,
+		Group:    param.Group,
+		Content:  param.Content,
+		Type:     param.Type,
+		OnChange: param.OnChange,
+		Native:   param,
+	}
+}
+
+// sdkParam converts the param of the core back, the fields unknown to the core, such as the AppName
+// and the Tag, are restored from the native one.
+func sdkParam(param core.ConfigParam) vo.ConfigParam {
+	native, _ := param.Native.(vo.ConfigParam)
+	native.DataId = param.DataId
+	native.Group = param.Group
+	native.Content = param.Content
+	native.Type = param.Type
+	native.OnChange = param.OnChange
+	return native
+}
@@ -14,44 +14,26 @@
 
 package nacos
 
-import (
-	"os"
-	"strconv"
-
-	"github.com/cloudwego/kitex/pkg/klog"
-)
+import "github.com/kitex-contrib/config-nacos/core"
 
 // keep consistent with the env with alicloud
 const (
-	NacosAliServerAddrEnv = "serverAddr"
-	NacosAliPortEnv       = "serverPort"
-	NacosAliNamespaceEnv  = "namespace"
+	NacosAliServerAddrEnv = core.NacosAliServerAddrEnv
+	NacosAliPortEnv       = core.NacosAliPortEnv
+	NacosAliNamespaceEnv  = core.NacosAliNamespaceEnv
 )
 
 // NacosPort Get Nacos port from environment variables
 func NacosPort() uint64 {
-	portText := os.Getenv(NacosAliPortEnv)
-	if len(portText) == 0 {
-		return NacosDefaultPort
-	}
-	port, err := strconv.ParseInt(portText, 10, 64)
-	if err != nil {
-		klog.Errorf("ParseInt failed,err:%s", err.Error())
-		return NacosDefaultPort
-	}
-	return uint64(port)
+	return core.NacosPort()
 }
 
 // NacosAddr Get Nacos addr from environment variables
 func NacosAddr() string {
-	addr := os.Getenv(NacosAliServerAddrEnv)
-	if len(addr) == 0 {
-		return NacosDefaultServerAddr
-	}
-	return addr
+	return core.NacosAddr()
 }
 
 // NacosNameSpaceId Get Nacos namespace id from environment variables
 func NacosNameSpaceId() string {
-	return os.Getenv(NacosDefaultConfigGroup)
+	return core.NacosNameSpaceId()
 }
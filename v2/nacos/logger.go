@@ -4,7 +4,7 @@
 // you may not use this file except in compliance with the License.
 // You may obtain a copy of the License at
 //
-//     http://www.apache.org/licenses/LICENSE-2.0
+//	http://www.apache.org/licenses/LICENSE-2.0
 //
 // Unless required by applicable law or agreed to in writing, software
 // distributed under the License is distributed on an "AS IS" BASIS,
@@ -12,31 +12,15 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package utils
+package nacos
 
 import (
-	"sort"
-	"testing"
+	"github.com/nacos-group/nacos-sdk-go/v2/common/logger"
 
-	"github.com/stretchr/testify/assert"
+	"github.com/kitex-contrib/config-nacos/core"
 )
 
-func TestSet(t *testing.T) {
-	ts := &ThreadSafeSet{}
-	m1 := Set{
-		"h1": true,
-		"h2": true,
-	}
-	got := ts.DiffAndEmplace(m1)
-	assert.Equal(t, []string([]string{}), got)
-	assert.Equal(t, m1, ts.s)
-
-	m2 := Set{
-		"h3": true,
-		"h4": true,
-	}
-	got = ts.DiffAndEmplace(m2)
-	sort.Strings(got)
-	assert.Equal(t, []string([]string{"h1", "h2"}), got)
-	assert.Equal(t, m2, ts.s)
+// NewCustomNacosLogger returns the logger forwarding the logs of the nacos sdk to klog.
+func NewCustomNacosLogger() logger.Logger {
+	return core.NacosLogger{}
 }
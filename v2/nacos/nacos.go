@@ -11,36 +11,18 @@
 // WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
 // See the License for the specific language governing permissions and
 // limitations under the License.
+
 package nacos
 
 import (
-	"bytes"
-	"sync"
-	"text/template"
-
-	"github.com/cloudwego/kitex/pkg/klog"
 	"github.com/nacos-group/nacos-sdk-go/v2/clients"
 	"github.com/nacos-group/nacos-sdk-go/v2/clients/config_client"
 	"github.com/nacos-group/nacos-sdk-go/v2/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/v2/common/logger"
 	"github.com/nacos-group/nacos-sdk-go/v2/vo"
-)
-
-// callbackHandler ...
-type callbackHandler func(namespace, group, dataId, data string)
 
-type configParam struct {
-	DataID string
-	Group  string
-}
-
-// NOTE: the nacos client use namespace + dataID + group as cache key, and the namespace
-// in client is fixed.
-func configParamKey(in vo.ConfigParam) configParam {
-	return configParam{
-		DataID: in.DataId,
-		Group:  in.Group,
-	}
-}
+	"github.com/kitex-contrib/config-nacos/core"
+)
 
 // Client the wrapper of nacos client.
 type Client interface {
@@ -51,16 +33,18 @@ type Client interface {
 	DeregisterConfig(vo.ConfigParam, int64) error
 }
 
-type client struct {
-	ncli config_client.IConfigClient
-	// support customise parser
-	parser               ConfigParser
-	groupTemplate        *template.Template
-	serverDataIDTemplate *template.Template
-	clientDataIDTemplate *template.Template
-
-	handlerMutex sync.RWMutex
-	handlers     map[configParam]map[int64]callbackHandler
+// VersionedClient the client keeping the local versions of the configs, which the client returned by
+// NewClient implements, e.g. nacosClient.(nacos.VersionedClient).Rollback(param, version).
+type VersionedClient interface {
+	Client
+	// History returns the local versions of the config, from the oldest to the latest.
+	History(vo.ConfigParam) []core.Version
+	// Rollback applies an earlier version locally and pins it until the remote config changes or Unpin is called.
+	Rollback(param vo.ConfigParam, version int64) error
+	// Unpin clears the pinned version and applies the remote config again.
+	Unpin(vo.ConfigParam) error
+	// Status returns the apply status of the config.
+	Status(vo.ConfigParam) core.Status
 }
 
 // Options nacos config options. All the fields have default value.
@@ -72,10 +56,14 @@ type Options struct {
 	Group              string
 	ServerDataIDFormat string
 	ClientDataIDFormat string
-	Password           string
-	Username           string
-	ConfigParser       ConfigParser
-	GrpcPort           uint64
+	// CustomLogger replaces the global logger of the nacos sdk, which forwards the logs to klog by default.
+	CustomLogger logger.Logger
+	Password     string
+	Username     string
+	ConfigParser ConfigParser
+	// GrpcPort the grpc port of nacos, the port plus 1000 by default.
+	GrpcPort uint64
+	core.GovernanceOptions
 }
 
 // NewClient Create a default Nacos client
@@ -89,24 +77,15 @@ func NewClient(opts Options) (Client, error) {
 	if opts.NamespaceID == "" {
 		opts.NamespaceID = NacosNameSpaceId()
 	}
-	if opts.Group == "" {
-		opts.Group = NacosDefaultConfigGroup
-	}
-	if opts.ConfigParser == nil {
-		opts.ConfigParser = defaultConfigParse()
-	}
-	if opts.ServerDataIDFormat == "" {
-		opts.ServerDataIDFormat = NacosDefaultServerDataID
+	if opts.CustomLogger == nil {
+		opts.CustomLogger = NewCustomNacosLogger()
 	}
-	if opts.ClientDataIDFormat == "" {
-		opts.ClientDataIDFormat = NacosDefaultClientDataID
+	var serverOptions []constant.ServerOption
+	if opts.GrpcPort != 0 {
+		serverOptions = append(serverOptions, constant.WithGrpcPort(opts.GrpcPort))
 	}
-	if opts.GrpcPort == 0 {
-		opts.GrpcPort = NacosDefaultGrpcPorc
-	}
-
 	sc := []constant.ServerConfig{
-		*constant.NewServerConfig(opts.Address, opts.Port),
+		*constant.NewServerConfig(opts.Address, opts.Port, serverOptions...),
 	}
 
 	cc := constant.ClientConfig{
@@ -128,158 +107,93 @@ func NewClient(opts Options) (Client, error) {
 	if err != nil {
 		return nil, err
 	}
-	groupTemplate, err := template.New("group").Parse(opts.Group)
-	if err != nil {
-		return nil, err
-	}
-	serverDataIDTemplate, err := template.New("serverDataID").Parse(opts.ServerDataIDFormat)
-	if err != nil {
-		return nil, err
-	}
-	clientDataIDTemplate, err := template.New("clientDataID").Parse(opts.ClientDataIDFormat)
+	// the nacos sdk v2 initializes the global logger when creating the client, replace it afterwards
+	logger.SetLogger(opts.CustomLogger)
+	cli, err := core.NewClient(&configClient{nacosClient}, core.Options{
+		Group:              opts.Group,
+		ServerDataIDFormat: opts.ServerDataIDFormat,
+		ClientDataIDFormat: opts.ClientDataIDFormat,
+		ConfigParser:       opts.ConfigParser,
+		GovernanceOptions:  opts.GovernanceOptions,
+	})
 	if err != nil {
 		return nil, err
 	}
-	c := &client{
-		ncli:                 nacosClient,
-		parser:               opts.ConfigParser,
-		groupTemplate:        groupTemplate,
-		serverDataIDTemplate: serverDataIDTemplate,
-		clientDataIDTemplate: clientDataIDTemplate,
-		handlers:             map[configParam]map[int64]callbackHandler{},
-	}
-	return c, nil
+	return &client{core: cli}, nil
 }
 
-// SetParser support customise parser
-func (c *client) SetParser(parser ConfigParser) {
-	c.parser = parser
+var _ VersionedClient = &client{}
+
+// client adapts the governance client of the core to the nacos sdk v2.
+type client struct {
+	core core.Client
 }
 
-func (c *client) render(cpc *ConfigParamConfig, t *template.Template) (string, error) {
-	var tpl bytes.Buffer
-	err := t.Execute(&tpl, cpc)
-	if err != nil {
-		return "", err
-	}
-	return tpl.String(), nil
+// SetParser support customise parser
+func (c *client) SetParser(parser ConfigParser) {
+	c.core.SetParser(parser)
 }
 
 // ServerConfigParam render server config parameters
 func (c *client) ServerConfigParam(cpc *ConfigParamConfig) (vo.ConfigParam, error) {
-	return c.configParam(cpc, c.serverDataIDTemplate)
+	param, err := c.core.ServerConfigParam(cpc)
+	return sdkParam(param), err
 }
 
 // ClientConfigParam render client config parameters
 func (c *client) ClientConfigParam(cpc *ConfigParamConfig) (vo.ConfigParam, error) {
-	return c.configParam(cpc, c.clientDataIDTemplate)
+	param, err := c.core.ClientConfigParam(cpc)
+	return sdkParam(param), err
 }
 
-// configParam render config parameters. All the parameters can be customized with CustomFunction.
-// ConfigParam explain:
-//  1. Type: data id format, support JSON and YAML, JSON by default. Could extend it by implementing the ConfigParser interface.
-//  2. Content: empty by default. Customize with CustomFunction.
-//  3. Group: DEFAULT_GROUP by default.
-//  4. ServerDataId: {{.ServerServiceName}}.{{.Category}} by default.
-//     ClientDataId: {{.ClientServiceName}}.{{.ServerServiceName}}.{{.Category}} by default.
-func (c *client) configParam(cpc *ConfigParamConfig, t *template.Template) (vo.ConfigParam, error) {
-	param := vo.ConfigParam{
-		Type:    "json",
-		Content: defaultContent,
-	}
-	var err error
-	param.DataId, err = c.render(cpc, t)
-	if err != nil {
-		return param, err
-	}
-	param.Group, err = c.render(cpc, c.groupTemplate)
-	if err != nil {
-		return param, err
-	}
-	return param, nil
+// RegisterConfigCallback register the callback function to nacos client.
+func (c *client) RegisterConfigCallback(param vo.ConfigParam, callback func(string, ConfigParser), uniqueID int64) {
+	c.core.RegisterConfigCallback(coreParam(param), callback, uniqueID)
 }
 
 // DeregisterConfig deregister the config.
-func (c *client) DeregisterConfig(cfg vo.ConfigParam, uniqueID int64) error {
-	key := configParamKey(cfg)
-	klog.Debugf("deregister key %v for uniqueID %d", key, uniqueID)
-	c.handlerMutex.Lock()
-	defer c.handlerMutex.Unlock()
-	handlers, ok := c.handlers[key]
-	if ok {
-		delete(handlers, uniqueID)
-	}
-	if len(handlers) == 0 {
-		klog.Debugf("the handlers for key %v is empty, cancel listen config from nacos", key)
-		return c.ncli.CancelListenConfig(cfg)
-	}
-	return nil
+func (c *client) DeregisterConfig(param vo.ConfigParam, uniqueID int64) error {
+	return c.core.DeregisterConfig(coreParam(param), uniqueID)
 }
 
-func (c *client) onChange(namespace, group, dataId, data string) {
-	handlers := make([]callbackHandler, 0, 5)
-	c.handlerMutex.RLock()
-	key := configParam{
-		DataID: dataId,
-		Group:  group,
-	}
-	for _, handler := range c.handlers[key] {
-		handlers = append(handlers, handler)
-	}
-	c.handlerMutex.RUnlock()
+// History implements VersionedClient.
+func (c *client) History(param vo.ConfigParam) []core.Version {
+	return c.core.History(coreParam(param))
+}
 
-	for _, handler := range handlers {
-		handler(namespace, group, dataId, data)
-	}
+// Rollback implements VersionedClient.
+func (c *client) Rollback(param vo.ConfigParam, version int64) error {
+	return c.core.Rollback(coreParam(param), version)
 }
 
-func (c *client) listenConfig(param vo.ConfigParam, uniqueID int64) {
-	key := configParamKey(param)
-	klog.Debugf("register key %v for uniqueID %d", key, uniqueID)
-	c.handlerMutex.Lock()
-	handlers, ok := c.handlers[key]
-	if !ok {
-		handlers = map[int64]callbackHandler{}
-		c.handlers[key] = handlers
-	}
-	handlers[uniqueID] = param.OnChange
-	c.handlerMutex.Unlock()
+// Unpin implements VersionedClient.
+func (c *client) Unpin(param vo.ConfigParam) error {
+	return c.core.Unpin(coreParam(param))
+}
 
-	if !ok {
-		klog.Debugf("the first time %v register, listen config from nacos", key)
-		err := c.ncli.ListenConfig(vo.ConfigParam{
-			DataId:   param.DataId,
-			Group:    param.Group,
-			Content:  param.Content,
-			Type:     param.Type,
-			OnChange: c.onChange,
-		})
-		// Performs only local connection and fails only when the input params are invalid
-		if err != nil {
-			panic(err)
-		}
-	}
+// Status implements VersionedClient.
+func (c *client) Status(param vo.ConfigParam) core.Status {
+	return c.core.Status(coreParam(param))
 }
 
-// RegisterConfigCallback register the callback function to nacos client.
-func (c *client) RegisterConfigCallback(param vo.ConfigParam,
-	callback func(string, ConfigParser), uniqueID int64,
-) {
-	param.OnChange = func(namespace, group, dataId, data string) {
-		klog.Debugf("[nacos] uniqueID %d config %s updated, namespace %s group %s dataId %s data %s",
-			uniqueID, param.DataId, namespace, group, dataId, data)
-		callback(data, c.parser)
-	}
+var _ core.ConfigClient = &configClient{}
 
-	// NOTE: does not ensure that GetConfig succeeds, the govern policy may not be correct if it fails here.
-	data, err := c.ncli.GetConfig(param)
-	if err != nil {
-		// If the initial connection fails and the reconnection is successful, the callback handler can also be invoked.
-		// Ignore the error here and print the error info.
-		klog.Warnf("get config %v from nacos failed %v", param, err)
-	}
+// configClient adapts the config client of the nacos sdk v2 to the core.
+type configClient struct {
+	ncli config_client.IConfigClient
+}
 
-	callback(data, c.parser)
+// GetConfig implements core.ConfigClient.
+func (c *configClient) GetConfig(param core.ConfigParam) (string, error) {
+	return c.ncli.GetConfig(sdkParam(param))
+}
+
+// ListenConfig implements core.ConfigClient.
+func (c *configClient) ListenConfig(param core.ConfigParam) error {
+	return c.ncli.ListenConfig(sdkParam(param))
+}
 
-	c.listenConfig(param, uniqueID)
+// CancelListenConfig implements core.ConfigClient.
+func (c *configClient) CancelListenConfig(param core.ConfigParam) error {
+	return c.ncli.CancelListenConfig(sdkParam(param))
 }
@@ -11,28 +11,29 @@
 // WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
 // See the License for the specific language governing permissions and
 // limitations under the License.
+
 package nacos
 
 import (
-	"fmt"
-	"sync"
+	"errors"
 	"testing"
 
 	"github.com/nacos-group/nacos-sdk-go/v2/model"
 	"github.com/nacos-group/nacos-sdk-go/v2/vo"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/kitex-contrib/config-nacos/core"
 )
 
 type fakeNacos struct {
-	sync.RWMutex
-	handlers map[configParam]callbackHandler
-}
-
-func (fn *fakeNacos) CloseClient() {
+	params   []vo.ConfigParam
+	content  string
+	onChange func(namespace, group, dataId, data string)
 }
 
 func (fn *fakeNacos) GetConfig(param vo.ConfigParam) (string, error) {
-	return "", nil
+	fn.params = append(fn.params, param)
+	return fn.content, nil
 }
 
 func (fn *fakeNacos) PublishConfig(param vo.ConfigParam) (bool, error) {
@@ -43,17 +44,15 @@ func (fn *fakeNacos) DeleteConfig(param vo.ConfigParam) (bool, error) {
 	return false, nil
 }
 
-func (fn *fakeNacos) ListenConfig(params vo.ConfigParam) (err error) {
-	fn.Lock()
-	defer fn.Unlock()
-	fn.handlers[configParamKey(params)] = params.OnChange
+func (fn *fakeNacos) ListenConfig(param vo.ConfigParam) (err error) {
+	fn.params = append(fn.params, param)
+	fn.onChange = param.OnChange
 	return nil
 }
 
-func (fn *fakeNacos) CancelListenConfig(params vo.ConfigParam) (err error) {
-	fn.Lock()
-	defer fn.Unlock()
-	delete(fn.handlers, configParamKey(params))
+func (fn *fakeNacos) CancelListenConfig(param vo.ConfigParam) (err error) {
+	fn.params = append(fn.params, param)
+	fn.onChange = nil
 	return nil
 }
 
@@ -61,135 +60,45 @@ func (fn *fakeNacos) SearchConfig(param vo.SearchConfigParam) (*model.ConfigPage
 	return nil, nil
 }
 
-func (fn *fakeNacos) PublishAggr(param vo.ConfigParam) (published bool, err error) {
-	return false, nil
-}
-
-func (fn *fakeNacos) change(cfg configParam, data string) {
-	fn.Lock()
-	defer fn.Unlock()
-
-	handler, ok := fn.handlers[cfg]
-	if !ok {
-		return
+func (fn *fakeNacos) CloseClient() {}
+
+func TestConfigClient(t *testing.T) {
+	fake := &fakeNacos{content: `{"qps": 1}`}
+	c, err := core.NewClient(&configClient{fake}, core.Options{Group: "g1"})
+	assert.Nil(t, err)
+	cli := CoreClient(&client{core: c}, []CustomFunction{func(param *vo.ConfigParam) {
+		param.AppName = "app"
+		param.Type = "yaml"
+	}})
+
+	param, err := cli.ServerConfigParam(&ConfigParamConfig{Category: "limit", ServerServiceName: "svc"})
+	assert.Nil(t, err)
+	assert.Equal(t, "yaml", param.Type)
+	var applied []string
+	id := GetUniqueID()
+	cli.RegisterConfigCallback(param, func(data string, parser ConfigParser) {
+		applied = append(applied, data)
+		core.Reject(parser, errors.New("rejected"))
+	}, id)
+	fake.onChange("", "g1", "svc.limit", `{"qps": 2}`)
+	assert.Equal(t, []string{`{"qps": 1}`, `{"qps": 2}`}, applied)
+	assert.Equal(t, uint64(2), cli.Status(param).Rejected)
+
+	// the clients implementing only Client have no versions
+	unversioned := CoreClient(struct{ Client }{&client{core: c}}, nil)
+	assert.Empty(t, unversioned.History(param))
+	assert.Equal(t, core.Status{}, unversioned.Status(param))
+	assert.Equal(t, errUnversioned, unversioned.Rollback(param, 1))
+	assert.Equal(t, errUnversioned, unversioned.Unpin(param))
+
+	assert.Nil(t, cli.DeregisterConfig(param, id))
+	assert.Nil(t, fake.onChange)
+	assert.Equal(t, 3, len(fake.params))
+	for _, p := range fake.params {
+		assert.Equal(t, "svc.limit", p.DataId)
+		assert.Equal(t, "g1", p.Group)
+		// the fields unknown to the core are kept
+		assert.Equal(t, "app", p.AppName)
+		assert.Equal(t, "yaml", p.Type)
 	}
-	fmt.Println("find handers ", fn.handlers, "cfg ", cfg, " data", data)
-	handler("", cfg.Group, cfg.DataID, data)
-}
-
-func TestRegisterAndDeRegister(t *testing.T) {
-	fake := &fakeNacos{
-		handlers: map[configParam]callbackHandler{},
-	}
-	c := &client{
-		ncli:     fake,
-		handlers: map[configParam]map[int64]callbackHandler{},
-	}
-
-	var gotlock sync.Mutex
-	gots := make(map[configParam]map[int64]string)
-	key := configParam{
-		Group:  "g1",
-		DataID: "d1",
-	}
-
-	id1 := GetUniqueID()
-
-	var wg sync.WaitGroup
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		// register
-		c.RegisterConfigCallback(vo.ConfigParam{
-			DataId: "d1",
-			Group:  "g1",
-		}, func(s string, cp ConfigParser) {
-			gotlock.Lock()
-			defer gotlock.Unlock()
-			ids, ok := gots[key]
-			if !ok {
-				ids = map[int64]string{}
-				gots[key] = ids
-			}
-			ids[id1] = s
-		}, id1)
-	}()
-
-	id2 := GetUniqueID()
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		c.RegisterConfigCallback(vo.ConfigParam{
-			DataId: "d1",
-			Group:  "g1",
-		}, func(s string, cp ConfigParser) {
-			gotlock.Lock()
-			defer gotlock.Unlock()
-			ids, ok := gots[key]
-			if !ok {
-				ids = map[int64]string{}
-				gots[key] = ids
-			}
-			ids[id2] = s
-		}, id2)
-	}()
-	wg.Wait()
-
-	// first change
-	fake.change(configParam{
-		DataID: "d1",
-		Group:  "g1",
-	}, "first change")
-
-	assert.Equal(t, map[configParam]map[int64]string{
-		{
-			Group:  "g1",
-			DataID: "d1",
-		}: {
-			id1: "first change",
-			id2: "first change",
-		},
-	}, gots)
-
-	// second change
-	c.DeregisterConfig(vo.ConfigParam{
-		DataId: "d1",
-		Group:  "g1",
-	}, id2)
-
-	fake.change(configParam{
-		DataID: "d1",
-		Group:  "g1",
-	}, "second change")
-
-	assert.Equal(t, map[configParam]map[int64]string{
-		{
-			Group:  "g1",
-			DataID: "d1",
-		}: {
-			id1: "second change",
-			id2: "first change",
-		},
-	}, gots)
-
-	// third change
-	c.DeregisterConfig(vo.ConfigParam{
-		DataId: "d1",
-		Group:  "g1",
-	}, id1)
-
-	fake.change(configParam{
-		DataID: "d1",
-		Group:  "g1",
-	}, "third change")
-
-	assert.Equal(t, map[configParam]map[int64]string{
-		{
-			Group:  "g1",
-			DataID: "d1",
-		}: {
-			id1: "second change",
-			id2: "first change",
-		},
-	}, gots)
 }
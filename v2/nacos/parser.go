@@ -15,23 +15,21 @@
 package nacos
 
 import (
-	"fmt"
-
 	"github.com/nacos-group/nacos-sdk-go/v2/vo"
-	"sigs.k8s.io/yaml"
-)
 
-const (
-	NacosDefaultServerAddr   = "127.0.0.1"
-	NacosDefaultPort         = 8848
-	NacosDefaultGrpcPorc     = 9848
-	NacosDefaultConfigGroup  = "DEFAULT_GROUP"
-	NacosDefaultClientDataID = "{{.ClientServiceName}}.{{.ServerServiceName}}.{{.Category}}"
-	NacosDefaultServerDataID = "{{.ServerServiceName}}.{{.Category}}"
+	"github.com/kitex-contrib/config-nacos/core"
 )
 
 const (
-	defaultContent = ""
+	NacosDefaultServerAddr   = core.NacosDefaultServerAddr
+	NacosDefaultPort         = core.NacosDefaultPort
+	NacosDefaultGrpcPort     = 9848
+	NacosDefaultConfigGroup  = core.NacosDefaultConfigGroup
+	NacosDefaultClientDataID = core.NacosDefaultClientDataID
+	NacosDefaultServerDataID = core.NacosDefaultServerDataID
+
+	// Deprecated: use NacosDefaultGrpcPort instead.
+	NacosDefaultGrpcPorc = NacosDefaultGrpcPort
 )
 
 // CustomFunction use for customize the config parameters.
@@ -39,33 +37,7 @@ type CustomFunction func(*vo.ConfigParam)
 
 // ConfigParamConfig use for render the dataId or group info by go template, ref: https://pkg.go.dev/text/template
 // The fixed key shows as below.
-type ConfigParamConfig struct {
-	Category          string
-	ClientServiceName string
-	ServerServiceName string
-}
-
-var _ ConfigParser = &parser{}
-
-// ConfigParser the parser for nacos config.
-type ConfigParser interface {
-	Decode(kind, data string, config interface{}) error
-}
-
-type parser struct{}
-
-// Decode decodes the data to struct in specified format.
-func (p *parser) Decode(kind, data string, config interface{}) error {
-	switch kind {
-	case "yaml", "json":
-		// since YAML is a superset of JSON, it can parse JSON using a YAML parser
-		return yaml.Unmarshal([]byte(data), config)
-	default:
-		return fmt.Errorf("unsupported config data type %s", kind)
-	}
-}
+type ConfigParamConfig = core.ConfigParamConfig
 
-// DefaultConfigParse default nacos config parser.
-func defaultConfigParse() ConfigParser {
-	return &parser{}
-}
+// ConfigParser the parser for nacos config, the config types are the same as the ones of the core.
+type ConfigParser = core.ConfigParser
@@ -4,7 +4,7 @@
 // you may not use this file except in compliance with the License.
 // You may obtain a copy of the License at
 //
-//     http://www.apache.org/licenses/LICENSE-2.0
+//	http://www.apache.org/licenses/LICENSE-2.0
 //
 // Unless required by applicable law or agreed to in writing, software
 // distributed under the License is distributed on an "AS IS" BASIS,
@@ -15,76 +15,45 @@
 package degradation
 
 import (
-	"context"
-	"sync"
-	"sync/atomic"
-
-	"github.com/bytedance/gopkg/lang/fastrand"
-	"github.com/cloudwego/kitex/pkg/acl"
-	"github.com/pkg/errors"
+	"github.com/kitex-contrib/config-nacos/pkg/degradation"
 )
 
-var errorDegradation = errors.New("rejected by client degradation config")
-
-// DegradationConfig is policy config of degradator.
-// DON'T FORGET to update DeepCopy() and Equals() if you add new fields.
-type Config struct {
-	Enable     bool `json:"enable"`
-	Percentage int  `json:"percentage"`
-}
+// The degradation is shared with the v1 module.
 
-type Container struct {
-	sync.RWMutex
-	config atomic.Value
-}
+type (
+	Config    = degradation.Config
+	Configs   = degradation.Configs
+	Container = degradation.Container
+	Rule      = degradation.Rule
+	Match     = degradation.Match
 
-var defaultConfig = &Config{Enable: false, Percentage: 0}
+	ErrorKind   = degradation.ErrorKind
+	ErrorConfig = degradation.ErrorConfig
+	Error       = degradation.Error
 
-// GetDefaultDegradationConfig return defaultConfig of degradation.
-func GetDefaultDegradationConfig() *Config {
-	return defaultConfig.DeepCopy()
-}
+	Schedule = degradation.Schedule
+	Window   = degradation.Window
+)
 
-func NewDegradationContainer() *Container {
-	degradationContainer := &Container{}
-	degradationContainer.config.Store(GetDefaultDegradationConfig())
-	return degradationContainer
-}
+// ErrDegraded is the default rejection error, errors.Is reports true for it and the errors of ErrorKindError.
+var ErrDegraded = degradation.ErrDegraded
 
-func (s *Container) NotifyPolicyChange(cfg *Config) {
-	s.config.Store(cfg)
-}
+const (
+	// Wildcard the key of the default config for the methods without one.
+	Wildcard = degradation.Wildcard
+	// ClusterTag the tag of the callee cluster.
+	ClusterTag = degradation.ClusterTag
 
-func (s *Container) GetACLRule() acl.RejectFunc {
-	return func(ctx context.Context, request interface{}) (reason error) {
-		config := s.config.Load().(*Config)
-		if !config.Enable {
-			return nil
-		}
-		if fastrand.Intn(100) < config.Percentage {
-			return errorDegradation
-		}
-		return nil
-	}
-}
+	ErrorKindBizStatus = degradation.ErrorKindBizStatus
+	ErrorKindError     = degradation.ErrorKindError
+)
 
-// DeepCopy returns a full copy of DegradationConfig.
-func (c *Config) DeepCopy() *Config {
-	if c == nil {
-		return nil
-	}
-	return &Config{
-		Enable:     c.Enable,
-		Percentage: c.Percentage,
-	}
+// GetDefaultDegradationConfig return defaultConfig of degradation.
+func GetDefaultDegradationConfig() *Config {
+	return degradation.GetDefaultDegradationConfig()
 }
 
-func (c *Config) Equals(other *Config) bool {
-	if c == nil && other == nil {
-		return true
-	}
-	if c == nil || other == nil {
-		return false
-	}
-	return c.Enable == other.Enable && c.Percentage == other.Percentage
+// NewDegradationContainer creates the degradation container.
+func NewDegradationContainer() *Container {
+	return degradation.NewDegradationContainer()
 }
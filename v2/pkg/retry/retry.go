@@ -0,0 +1,33 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retry
+
+import (
+	"github.com/kitex-contrib/config-nacos/pkg/retry"
+)
+
+// The retry policies are shared with the v1 module.
+
+// ContainerKey the reserved key of the retry config for the limits of the retry container.
+const ContainerKey = retry.ContainerKey
+
+type (
+	Config         = retry.Config
+	Container      = retry.Container
+	CircuitBreaker = retry.CircuitBreaker
+	Policy         = retry.Policy
+	ResultRetry    = retry.ResultRetry
+	FieldPredicate = retry.FieldPredicate
+)
@@ -0,0 +1,41 @@
+// Copyright 2024 CloudWeGo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"github.com/kitex-contrib/config-nacos/pkg/schema"
+)
+
+// The schemas are shared with the v1 module.
+
+// Draft the JSON Schema draft of the generated schemas.
+const Draft = schema.Draft
+
+type Schema = schema.Schema
+
+// Categories returns the names of the categories which have schemas.
+func Categories() []string {
+	return schema.Categories()
+}
+
+// For returns the schema of the category, false if the category is unknown.
+func For(category string) (*Schema, bool) {
+	return schema.For(category)
+}
+
+// Generate generates the schema of the value by the json tags of its type.
+func Generate(v interface{}) *Schema {
+	return schema.Generate(v)
+}
@@ -4,7 +4,7 @@
 // you may not use this file except in compliance with the License.
 // You may obtain a copy of the License at
 //
-//     http://www.apache.org/licenses/LICENSE-2.0
+//	http://www.apache.org/licenses/LICENSE-2.0
 //
 // Unless required by applicable law or agreed to in writing, software
 // distributed under the License is distributed on an "AS IS" BASIS,
@@ -16,36 +16,27 @@ package server
 
 import (
 	"github.com/cloudwego/kitex/server"
+
+	nacosserver "github.com/kitex-contrib/config-nacos/internal/server"
 	"github.com/kitex-contrib/config-nacos/v2/nacos"
 	"github.com/kitex-contrib/config-nacos/v2/utils"
 )
 
-const (
-	limiterConfigName = "limit"
-)
+// The server governance is implemented by the internal package shared with the v1 module.
 
 // NacosServerSuite nacos server config suite, configure limiter config dynamically from nacos.
-type NacosServerSuite struct {
-	nacosClient nacos.Client
-	service     string
-	opts        utils.Options
-}
+type NacosServerSuite = nacosserver.NacosServerSuite
 
 // NewSuite service is the destination service.
 func NewSuite(service string, cli nacos.Client, opts ...utils.Option) *NacosServerSuite {
-	su := &NacosServerSuite{
-		service:     service,
-		nacosClient: cli,
-	}
+	su := utils.Options{}
 	for _, opt := range opts {
-		opt.Apply(&su.opts)
+		opt.Apply(&su)
 	}
-	return su
+	return nacosserver.NewSuite(service, nacos.CoreClient(cli, su.NacosCustomFunctions), su.Options)
 }
 
-// Options return a list client.Option
-func (s *NacosServerSuite) Options() []server.Option {
-	opts := make([]server.Option, 0, 2)
-	opts = append(opts, WithLimiter(s.service, s.nacosClient, s.opts))
-	return opts
+// WithLimiter sets the limiter config from nacos configuration center.
+func WithLimiter(dest string, nacosClient nacos.Client, opts utils.Options) server.Option {
+	return nacosserver.WithLimiter(dest, nacos.CoreClient(nacosClient, opts.NacosCustomFunctions), opts.Options)
 }
@@ -11,9 +11,11 @@
 // WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
 // See the License for the specific language governing permissions and
 // limitations under the License.
+
 package utils
 
 import (
+	"github.com/kitex-contrib/config-nacos/internal/utils"
 	"github.com/kitex-contrib/config-nacos/v2/nacos"
 )
 
@@ -25,4 +27,36 @@ type Option interface {
 // Options is used to initialize the nacos config suit or option.
 type Options struct {
 	NacosCustomFunctions []nacos.CustomFunction
+	// Options the governance options shared with the v1 module, such as the validators and the dry-run categories.
+	utils.Options
+}
+
+// option applies the governance option to the embedded options.
+type option struct {
+	opt utils.Option
+}
+
+// Apply implements Option.
+func (o *option) Apply(opts *Options) {
+	o.opt.Apply(&opts.Options)
+}
+
+// WithDryRun puts the categories in dry-run mode, all the categories if empty.
+func WithDryRun(categories ...string) Option {
+	return &option{opt: utils.WithDryRun(categories...)}
+}
+
+// WithSchemaValidation validates the payloads against the JSON Schemas of the categories before applying.
+func WithSchemaValidation() Option {
+	return &option{opt: utils.WithSchemaValidation()}
+}
+
+// WithStrictDecoding decodes the categories in strict mode, all the categories if empty.
+func WithStrictDecoding(categories ...string) Option {
+	return &option{opt: utils.WithStrictDecoding(categories...)}
+}
+
+// WithValidator registers the validator for the category, which runs after the built-in one.
+func WithValidator(category string, v Validator) Option {
+	return &option{opt: utils.WithValidator(category, v)}
 }